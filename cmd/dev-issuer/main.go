@@ -0,0 +1,124 @@
+// Command dev-issuer is a local stand-in OpenID Connect issuer for development
+// and testing. It publishes discovery metadata and a JWKS for a key generated
+// at startup and mints tokens for any subject:
+//
+//	curl 'http://localhost:9000/token?sub=alice&name=Alice&email=alice@example.com'
+//
+// Run the service with OIDC_ISSUER=http://localhost:9000 to accept them.
+// Never expose it outside of a development machine.
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const keyID = "dev"
+
+func main() {
+	port := getEnv("DEV_ISSUER_PORT", "9000")
+	issuer := getEnv("DEV_ISSUER_URL", "http://localhost:"+port)
+	audience := os.Getenv("OIDC_AUDIENCE")
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatal("Failed to generate signing key:", err)
+	}
+
+	r := gin.Default()
+
+	r.GET("/.well-known/openid-configuration", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"issuer":                                issuer,
+			"jwks_uri":                              issuer + "/jwks",
+			"token_endpoint":                        issuer + "/token",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+
+	r.GET("/jwks", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"keys": []gin.H{{
+				"kty": "RSA",
+				"kid": keyID,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+
+	// Mints a token: ?sub (required), name, email, aud (defaults to
+	// OIDC_AUDIENCE) and ttl in seconds (default 3600)
+	r.GET("/token", func(c *gin.Context) {
+		subject := c.Query("sub")
+		if subject == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "sub is required",
+			})
+			return
+		}
+		ttl, err := strconv.Atoi(c.DefaultQuery("ttl", "3600"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid ttl parameter",
+			})
+			return
+		}
+
+		now := time.Now()
+		claims := jwt.MapClaims{
+			"iss": issuer,
+			"sub": subject,
+			"iat": now.Unix(),
+			"exp": now.Add(time.Duration(ttl) * time.Second).Unix(),
+		}
+		if aud := c.DefaultQuery("aud", audience); aud != "" {
+			claims["aud"] = aud
+		}
+		if name := c.Query("name"); name != "" {
+			claims["name"] = name
+		}
+		if email := c.Query("email"); email != "" {
+			claims["email"] = email
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = keyID
+		signed, err := token.SignedString(key)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to sign token",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"access_token": signed,
+			"token_type":   "Bearer",
+			"expires_in":   ttl,
+		})
+	})
+
+	fmt.Printf("Development issuer %s starting on port %s\n", issuer, port)
+	log.Fatal(r.Run(":" + port))
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"roadmap/internal/models"
+)
+
+// runExport writes a roadmap archive, the same as GET /export
+func runExport(env *environment, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	roadmap := roadmapFlag(flags)
+	out := flags.String("out", "-", "output file, - for stdout")
+	changeLog := flags.Bool("change-log", false, "include the retained change log")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := noArguments(flags); err != nil {
+		return err
+	}
+
+	documentID, err := env.roadmap(*roadmap)
+	if err != nil {
+		return err
+	}
+	archive, err := env.svc.ExportArchive(documentID, *changeLog)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "-" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(archive); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported roadmap %s at version %d: %d teams, %d sprints, %d resources, %d tasks, %d changes\n",
+		documentID, archive.Roadmap.Version, len(archive.Teams), len(archive.Sprints), len(archive.Resources),
+		len(archive.Tasks), len(archive.ChangeLog))
+	return nil
+}
+
+// runImport restores an archive written by export, the same as POST /import
+func runImport(env *environment, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	roadmap := roadmapFlag(flags)
+	newRoadmap := flags.String("new", "", "create a roadmap with this name and import into it")
+	replace := flags.Bool("replace", false, "delete existing teams, sprints, resources and tasks first")
+	remapIDs := flags.Bool("remap-ids", false, "give every row a new id, e.g. to copy a roadmap within one database")
+	user := userFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("Usage: service import [--roadmap id | --new name] [--replace] [--remap-ids] [--user id] <file | ->")
+	}
+	if *roadmap != "" && *newRoadmap != "" {
+		return errors.New("--roadmap and --new are mutually exclusive")
+	}
+
+	var r io.Reader = os.Stdin
+	if name := flags.Arg(0); name != "-" {
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+	var archive models.Archive
+	if err := json.NewDecoder(r).Decode(&archive); err != nil {
+		return fmt.Errorf("failed to read %s: %w", flags.Arg(0), err)
+	}
+
+	userID, err := env.author(*user)
+	if err != nil {
+		return err
+	}
+	documentID, err := targetRoadmap(env, *roadmap, *newRoadmap)
+	if err != nil {
+		return err
+	}
+	result, err := env.svc.ImportArchive(documentID, &archive, &models.ImportOptions{
+		Replace:  *replace,
+		RemapIDs: *remapIDs,
+		UserID:   userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %s into roadmap %s: deleted %d rows, added %d teams, %d sprints, %d resources, %d tasks; version %d\n",
+		archive.Roadmap.Name, documentID, result.Deleted, result.Teams, result.Sprints, result.Resources, result.Tasks, result.Version)
+	return nil
+}
@@ -2,19 +2,77 @@ package main
 
 import (
 	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"os"
+	"strings"
 
-	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	_ "github.com/lib/pq"
 
-	"roadmap/internal/api"
 	"roadmap/internal/config"
+	"roadmap/internal/models"
 	"roadmap/internal/repository"
 	"roadmap/internal/service"
 )
 
+// command is a subcommand of the binary
+type command struct {
+	name    string
+	summary string
+	run     func(env *environment, args []string) error
+}
+
+var commands = []command{
+	{"serve", "start the HTTP server (default)", runServe},
+	{"migrate", "apply or inspect database migrations", runMigrate},
+	{"seed", "load a fixture set into a roadmap", runSeed},
+	{"export", "write a JSON backup archive of a roadmap", runExport},
+	{"import", "restore a backup archive into a roadmap", runImport},
+	{"replan", "re-run auto-planning and save the computed plan", runReplan},
+	{"repair-order", "rebuild broken row order of resources and tasks", runRepairOrder},
+	{"compact-log", "compact change logs of all roadmaps now", runCompactLog},
+	{"snapshot", "freeze the current plan of a roadmap as a baseline", runSnapshot},
+	{"annotate-attribution", "mark changes written without an author as unknown", runAnnotateAttribution},
+}
+
+// environment holds what commands share: configuration, database and the
+// service layer on top of it
+type environment struct {
+	cfg  *config.Config
+	db   *sql.DB
+	repo *repository.Repository
+	svc  *service.Service
+}
+
+// cliUserID is the author of changes made by commands unless --user is given
+var cliUserID = uuid.NewSHA1(uuid.NameSpaceOID, []byte("roadmap/cli")).String()
+
 func main() {
+	// Without a command the binary serves, as it did before it had commands
+	name, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" || (len(os.Args) > 1 && (os.Args[1] == "-h" || os.Args[1] == "--help")) {
+		usage()
+		return
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == name {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", name)
+		usage()
+		os.Exit(2)
+	}
+
 	// Load configuration
 	cfg := config.Load()
 
@@ -30,55 +88,69 @@ func main() {
 		log.Fatal("Failed to ping database:", err)
 	}
 
-	// Initialize repositories
 	repo := repository.New(db)
-
-	// Initialize services
-	svc := service.New(repo)
-
-	// Initialize API handlers
-	handlers := api.New(svc)
-
-	// Setup Gin router
-	r := gin.Default()
-
-	// Add CORS middleware
-	r.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
+	env := &environment{cfg: cfg, db: db, repo: repo, svc: service.New(repo)}
+	if err := cmd.run(env, args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
 		}
+		log.Fatal(err)
+	}
+}
 
-		c.Next()
-	})
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [arguments]\n\nCommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-20s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nThe database is taken from DATABASE_URL. Run a command with -h for its flags.\n")
+}
 
-	// API routes
-	api := r.Group("/api/v1")
-	{
-		// Version endpoint - lightweight endpoint for checking current version
-		api.GET("/version", handlers.GetVersion)
+// roadmap resolves a --roadmap flag value, defaulting to the default roadmap
+func (env *environment) roadmap(value string) (uuid.UUID, error) {
+	if value == "" {
+		return env.svc.GetDefaultDocumentID()
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid roadmap id %q", value)
+	}
+	if _, err := env.svc.GetRoadmap(id); err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
 
-		// Data endpoints
-		api.GET("/data", handlers.GetData)
-		api.GET("/data/diff/:fromVersion", handlers.GetDataDiff)
-		api.PUT("/data", handlers.UpdateData)
+// author validates a --user flag value; the default command line user is
+// registered so that its changes show a name in diffs and the audit log
+func (env *environment) author(userID string) (string, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return "", fmt.Errorf("invalid user id %q: must be a valid UUID", userID)
 	}
+	if id.String() == cliUserID {
+		_, err := env.svc.RegisterUser(&models.RegisterUserRequest{UserID: cliUserID, DisplayName: "Command line"})
+		if err != nil {
+			return "", err
+		}
+	}
+	return id.String(), nil
+}
 
-	// Health check
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
+// roadmapFlag registers the --roadmap flag shared by commands working on one roadmap
+func roadmapFlag(flags *flag.FlagSet) *string {
+	return flags.String("roadmap", "", "roadmap id (default: the default roadmap)")
+}
 
-	// Start server
-	port := cfg.Port
-	if port == "" {
-		port = "8080"
-	}
+// userFlag registers the --user flag naming the author of the changes
+func userFlag(flags *flag.FlagSet) *string {
+	return flags.String("user", cliUserID, "user id recorded as the author of the changes")
+}
 
-	fmt.Printf("Server starting on port %s\n", port)
-	log.Fatal(r.Run(":" + port))
+// noArguments rejects positional arguments left after the flags
+func noArguments(flags *flag.FlagSet) error {
+	if flags.NArg() > 0 {
+		return fmt.Errorf("%s: unexpected arguments %q", flags.Name(), flags.Args())
+	}
+	return nil
 }
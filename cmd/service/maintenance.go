@@ -0,0 +1,164 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"roadmap/internal/models"
+)
+
+// runReplan re-runs auto-planning of a roadmap and saves the changed tasks
+func runReplan(env *environment, args []string) error {
+	flags := flag.NewFlagSet("replan", flag.ContinueOnError)
+	roadmap := roadmapFlag(flags)
+	user := userFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := noArguments(flags); err != nil {
+		return err
+	}
+
+	documentID, err := env.roadmap(*roadmap)
+	if err != nil {
+		return err
+	}
+	userID, err := env.author(*user)
+	if err != nil {
+		return err
+	}
+
+	result, err := env.svc.Replan(documentID, userID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Replanned roadmap %s over %d weeks: %d tasks changed; version %d\n",
+		documentID, result.Weeks, result.ChangedTasks, result.Version)
+	return nil
+}
+
+// runRepairOrder rebuilds broken prev/next lists of a roadmap
+func runRepairOrder(env *environment, args []string) error {
+	flags := flag.NewFlagSet("repair-order", flag.ContinueOnError)
+	roadmap := roadmapFlag(flags)
+	user := userFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := noArguments(flags); err != nil {
+		return err
+	}
+
+	documentID, err := env.roadmap(*roadmap)
+	if err != nil {
+		return err
+	}
+	userID, err := env.author(*user)
+	if err != nil {
+		return err
+	}
+
+	result, err := env.svc.RepairOrder(documentID, userID)
+	if err != nil {
+		return err
+	}
+	if result.Resources == 0 && result.Tasks == 0 {
+		fmt.Printf("Row order of roadmap %s is intact\n", documentID)
+		return nil
+	}
+	fmt.Printf("Repaired row order of roadmap %s: relinked %d resources and %d tasks; version %d\n",
+		documentID, result.Resources, result.Tasks, result.Version)
+	return nil
+}
+
+// runCompactLog compacts the change logs of all roadmaps once, as the server
+// does every COMPACTION_INTERVAL
+func runCompactLog(env *environment, args []string) error {
+	flags := flag.NewFlagSet("compact-log", flag.ContinueOnError)
+	retention := flags.Duration("retention", env.cfg.ChangeLogRetention, "keep changes newer than this (default: CHANGE_LOG_RETENTION)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := noArguments(flags); err != nil {
+		return err
+	}
+	if *retention <= 0 {
+		return fmt.Errorf("retention must be positive, got %s; set --retention or CHANGE_LOG_RETENTION", *retention)
+	}
+
+	results, err := env.svc.CompactChangeLogs(*retention)
+	for _, result := range results {
+		fmt.Printf("Compacted change log of roadmap %s: snapshot at version %d, pruned %d changes\n",
+			result.DocumentID, result.SnapshotVersion, result.PrunedChanges)
+	}
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Printf("No changes older than %s to compact\n", *retention)
+	}
+	return nil
+}
+
+// runSnapshot freezes the current plan of a roadmap as a named baseline,
+// e.g. from a weekly cron job
+func runSnapshot(env *environment, args []string) error {
+	flags := flag.NewFlagSet("snapshot", flag.ContinueOnError)
+	roadmap := roadmapFlag(flags)
+	user := userFlag(flags)
+	name := flags.String("name", "", `baseline name (default: "Snapshot <date>")`)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := noArguments(flags); err != nil {
+		return err
+	}
+
+	documentID, err := env.roadmap(*roadmap)
+	if err != nil {
+		return err
+	}
+	userID, err := env.author(*user)
+	if err != nil {
+		return err
+	}
+	if *name == "" {
+		*name = "Snapshot " + time.Now().Format("2006-01-02 15:04")
+	}
+
+	baseline, err := env.svc.CreateBaseline(documentID, &models.CreateBaselineRequest{Name: *name, UserID: userID})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created baseline %s %q of roadmap %s at version %d\n", baseline.ID, baseline.Name, documentID, baseline.Version)
+	return nil
+}
+
+// runAnnotateAttribution marks change_log entries written without an author
+// as unknown, grouping them into one annotated change set per roadmap. It is
+// safe to run repeatedly.
+func runAnnotateAttribution(env *environment, args []string) error {
+	flags := flag.NewFlagSet("annotate-attribution", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := noArguments(flags); err != nil {
+		return err
+	}
+
+	gaps, err := env.svc.AnnotateAttributionGap()
+	if err != nil {
+		return err
+	}
+	if len(gaps) == 0 {
+		fmt.Println("No unattributed changes found")
+		return nil
+	}
+	for _, gap := range gaps {
+		fmt.Printf("Roadmap %s: %d changes, versions %d..%d (%s - %s) annotated as change set %s\n",
+			gap.DocumentID, gap.Changes, gap.FromVersion, gap.ToVersion,
+			gap.StartedAt.Format("2006-01-02 15:04:05"), gap.EndedAt.Format("2006-01-02 15:04:05"), gap.ChangeSetID)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"roadmap/db"
+	"roadmap/internal/migrate"
+)
+
+const migrateUsage = `Usage: service migrate <command>
+
+Commands:
+  status           list changesets and whether they are applied
+  up [--dry-run]   apply pending changesets; --dry-run prints their SQL instead
+  sync --through <file>
+                   record the changesets up to and including the file as
+                   applied without running them, for databases migrated
+                   before the changesets were tracked`
+
+// runMigrate runs the migrate subcommand with its arguments
+func runMigrate(env *environment, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	migrator, err := newMigrator(env.db)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		pending := 0
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "FILE\tCHANGESET\tSTATE\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := ""
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Local().Format("2006-01-02 15:04:05")
+			}
+			if status.State != migrate.StateApplied {
+				pending++
+			}
+			fmt.Fprintf(w, "%s\t%s:%s\t%s\t%s\n", status.Filename, status.Author, status.ID, status.State, appliedAt)
+		}
+		w.Flush()
+		fmt.Printf("\n%d of %d changesets to apply\n", pending, len(statuses))
+		return nil
+
+	case "up":
+		flags := flag.NewFlagSet("migrate up", flag.ContinueOnError)
+		dryRun := flags.Bool("dry-run", false, "print the SQL of pending changesets without applying them")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		applied, err := migrator.Up(ctx, os.Stdout, *dryRun)
+		if err != nil {
+			return err
+		}
+		if *dryRun {
+			fmt.Printf("-- %d changesets to apply\n", applied)
+		} else {
+			fmt.Printf("%d changesets applied\n", applied)
+		}
+		return nil
+
+	case "sync":
+		flags := flag.NewFlagSet("migrate sync", flag.ContinueOnError)
+		through := flags.String("through", "", "last changelog file already applied to the database, e.g. 005_fix_log_trigger.sql")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *through == "" {
+			return fmt.Errorf("migrate sync requires --through <file>, the last changelog file the database already has\n\n%s", migrateUsage)
+		}
+		synced, err := migrator.Sync(ctx, os.Stdout, *through)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d changesets marked as applied\n", synced)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown migrate command %q\n\n%s", args[0], migrateUsage)
+	}
+}
+
+// migrateOnStart applies pending changesets before the server starts
+func migrateOnStart(database *sql.DB) error {
+	migrator, err := newMigrator(database)
+	if err != nil {
+		return err
+	}
+	_, err = migrator.Up(context.Background(), os.Stdout, false)
+	return err
+}
+
+// newMigrator creates a migrator for the changelog embedded in the binary
+func newMigrator(database *sql.DB) (*migrate.Migrator, error) {
+	changesets, err := migrate.Load(db.Changelog, db.ChangelogDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load changelog: %w", err)
+	}
+	return migrate.New(database, changesets), nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+	"roadmap/internal/seed"
+)
+
+// runSeed implements `seed <set> [flags]`
+func runSeed(env *environment, args []string) error {
+	usage := fmt.Sprintf("Usage: service seed <%s> [--roadmap id | --new name] [--reset] [--teams n] [--tasks n] [--random-seed n]",
+		strings.Join(seed.Names(), "|"))
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return errors.New(usage)
+	}
+	name := args[0]
+
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	roadmap := roadmapFlag(flags)
+	newRoadmap := flags.String("new", "", "create a roadmap with this name and seed it")
+	reset := flags.Bool("reset", false, "delete existing teams, sprints, resources and tasks first")
+	teams := flags.Int("teams", 20, "number of teams of the large set")
+	tasks := flags.Int("tasks", 2000, "number of tasks of the large set")
+	randomSeed := flags.Int64("random-seed", 1, "random seed of the large set")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if err := noArguments(flags); err != nil {
+		return err
+	}
+	if *roadmap != "" && *newRoadmap != "" {
+		return errors.New("--roadmap and --new are mutually exclusive")
+	}
+
+	var fixture *seed.Fixture
+	var err error
+	if name == seed.Large {
+		fixture, err = seed.Generate(*teams, *tasks, *randomSeed)
+	} else {
+		fixture, err = seed.Load(name)
+	}
+	if err != nil {
+		return err
+	}
+
+	documentID, err := targetRoadmap(env, *roadmap, *newRoadmap)
+	if err != nil {
+		return err
+	}
+
+	// Seeded changes are attributed to a named user in diffs and the audit log
+	if _, err := env.svc.RegisterUser(&models.RegisterUserRequest{UserID: seed.UserID, DisplayName: "Seed data"}); err != nil {
+		return err
+	}
+
+	result, err := seed.Apply(env.svc, documentID, fixture, *reset, seed.UserID)
+	if errors.Is(err, seed.ErrNotEmpty) {
+		return fmt.Errorf("%w; pass --reset to replace it", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Seeded %s into roadmap %s: deleted %d rows, added %d teams, %d sprints, %d resources, %d tasks; version %d\n",
+		name, documentID, result.Deleted, result.Teams, result.Sprints, result.Resources, result.Tasks, result.Version)
+	return nil
+}
+
+// targetRoadmap returns the roadmap to write to: a new one when a name is
+// given, otherwise the --roadmap one
+func targetRoadmap(env *environment, roadmap, newRoadmap string) (uuid.UUID, error) {
+	if newRoadmap == "" {
+		return env.roadmap(roadmap)
+	}
+	created, err := env.svc.CreateRoadmap("", &models.CreateRoadmapRequest{Name: newRoadmap})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	fmt.Printf("Created roadmap %s\n", created.ID)
+	return created.ID, nil
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"roadmap/internal/api"
+	"roadmap/internal/auth"
+	"roadmap/internal/config"
+	"roadmap/internal/export"
+	"roadmap/internal/service"
+)
+
+// runServe starts the HTTP server
+func runServe(env *environment, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("serve takes no arguments, got %q", args)
+	}
+
+	cfg, svc := env.cfg, env.svc
+
+	// Apply pending migrations before serving
+	if cfg.MigrateOnStart {
+		if err := migrateOnStart(env.db); err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+	}
+
+	// Compact change_log in the background
+	if cfg.ChangeLogRetention > 0 && cfg.CompactionInterval > 0 {
+		go svc.RunChangeLogCompaction(cfg.CompactionInterval, cfg.ChangeLogRetention)
+	}
+
+	// Initialize API handlers
+	handlers := api.New(svc)
+	if font, err := export.LoadFont(cfg.PDFFont); err != nil {
+		log.Printf("PDF rendering is disabled: %v", err)
+	} else {
+		handlers.EnablePDF(font)
+	}
+
+	// Authenticate API requests when an identity provider or API tokens are configured
+	authenticator, err := newAuthenticator(cfg, svc)
+	if err != nil {
+		return fmt.Errorf("failed to configure authentication: %w", err)
+	}
+	var middleware, calendarMiddleware []gin.HandlerFunc
+	if authenticator != nil {
+		middleware = append(middleware, api.Authenticate(authenticator))
+		// Calendar apps subscribe to a URL and cannot send headers
+		calendarMiddleware = append(calendarMiddleware,
+			api.Authenticate(auth.Chain{auth.NewQueryAPITokens(svc), authenticator}))
+		// Roles are only meaningful for authenticated users
+		svc.EnableAccessControl(splitList(cfg.Admins))
+	} else {
+		log.Printf("Authentication is disabled: userId from request bodies is trusted")
+	}
+
+	// Setup Gin router
+	r := gin.Default()
+	r.Use(api.RequestID())
+
+	// Add CORS middleware
+	r.Use(func(c *gin.Context) {
+		c.Header("Access-Control-Allow-Origin", "*")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
+		c.Header("Access-Control-Expose-Headers", "X-Request-ID")
+
+		if c.Request.Method == "OPTIONS" {
+			c.AbortWithStatus(204)
+			return
+		}
+
+		c.Next()
+	})
+
+	// API routes
+	api := r.Group("/api/v1", middleware...)
+	{
+		// Authenticated user and user directory
+		api.GET("/me", handlers.GetCurrentUser)
+		api.PUT("/me", handlers.RegisterCurrentUser)
+		api.GET("/users", handlers.GetUsers)
+		api.GET("/users/:userId", handlers.GetUser)
+
+		// Roadmap documents
+		api.GET("/roadmaps", handlers.GetRoadmaps)
+		api.POST("/roadmaps", handlers.CreateRoadmap)
+		api.GET("/roadmaps/:roadmapId", handlers.GetRoadmap)
+
+		// Unscoped routes address the default roadmap document
+		registerDocumentRoutes(api, handlers)
+		registerDocumentRoutes(api.Group("/roadmaps/:roadmapId"), handlers)
+	}
+
+	// Calendar subscription, also with a read API token as ?token=
+	calendar := r.Group("/api/v1", calendarMiddleware...)
+	{
+		calendar.GET("/calendar.ics", handlers.GetCalendar)
+		calendar.GET("/roadmaps/:roadmapId/calendar.ics", handlers.GetCalendar)
+	}
+
+	// Health check
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(200, gin.H{"status": "ok"})
+	})
+
+	// Start server
+	port := cfg.Port
+	if port == "" {
+		port = "8080"
+	}
+
+	fmt.Printf("Server starting on port %s\n", port)
+	return r.Run(":" + port)
+}
+
+// newAuthenticator builds the configured authenticators, or returns nil when
+// none is configured. API tokens issued through the API are accepted whenever
+// authentication is on.
+func newAuthenticator(cfg *config.Config, svc *service.Service) (auth.Authenticator, error) {
+	var chain auth.Chain
+	if cfg.APITokens != "" {
+		tokens, err := auth.ParseStaticTokens(cfg.APITokens)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, tokens)
+	}
+	if cfg.OIDCIssuer != "" {
+		chain = append(chain, auth.NewOIDC(cfg.OIDCIssuer, cfg.OIDCAudience, cfg.OIDCUserClaim))
+	}
+	if len(chain) == 0 {
+		return nil, nil
+	}
+	return append(auth.Chain{auth.NewAPITokens(svc)}, chain...), nil
+}
+
+// splitList splits a comma separated list, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// registerDocumentRoutes registers the routes operating on a single roadmap document
+func registerDocumentRoutes(group *gin.RouterGroup, handlers *api.Handlers) {
+	// Version endpoint - lightweight endpoint for checking current version
+	group.GET("/version", handlers.GetVersion)
+
+	// Data endpoints
+	group.GET("/data", handlers.GetData)
+	group.GET("/data/diff", handlers.GetVersionDiff)
+	group.GET("/data/diff/:fromVersion", handlers.GetDataDiff)
+	group.PUT("/data", handlers.UpdateData)
+
+	// Backup and restore
+	group.GET("/export", handlers.ExportArchive)
+	group.POST("/import", handlers.ImportArchive)
+	group.GET("/export.csv", handlers.ExportCSV)
+	group.GET("/export.xlsx", handlers.ExportXLSX)
+	group.GET("/export.mmd", handlers.ExportMermaid)
+	group.GET("/export.puml", handlers.ExportPlantUML)
+	group.POST("/import/table", handlers.ImportTable)
+
+	// Static renderings
+	group.GET("/render/gantt.svg", handlers.RenderGanttSVG)
+	group.GET("/render/gantt.pdf", handlers.RenderGanttPDF)
+
+	// Dry-run planning simulation
+	group.POST("/simulate", handlers.Simulate)
+
+	// Task endpoints
+	group.POST("/tasks/bulk", handlers.BulkUpdateTasks)
+	group.POST("/tasks/:id/duplicate", handlers.DuplicateTask)
+
+	// Resource endpoints
+	group.POST("/resources/:id/duplicate", handlers.DuplicateResource)
+
+	// What-if scenario endpoints
+	group.GET("/scenarios", handlers.GetScenarios)
+	group.POST("/scenarios", handlers.CreateScenario)
+	group.GET("/scenarios/:scenarioId", handlers.GetScenario)
+	group.PUT("/scenarios/:scenarioId/data", handlers.UpdateScenario)
+	group.GET("/scenarios/:scenarioId/compare", handlers.CompareScenario)
+	group.POST("/scenarios/:scenarioId/merge", handlers.MergeScenario)
+	group.DELETE("/scenarios/:scenarioId", handlers.DiscardScenario)
+
+	// Baseline endpoints
+	group.GET("/baselines", handlers.GetBaselines)
+	group.POST("/baselines", handlers.CreateBaseline)
+	group.GET("/baselines/:baselineId", handlers.GetBaseline)
+	group.GET("/baselines/:baselineId/data", handlers.GetBaselineData)
+	group.GET("/baselines/:baselineId/report", handlers.GetBaselineReport)
+	group.DELETE("/baselines/:baselineId", handlers.DeleteBaseline)
+
+	// Audit log endpoints
+	group.GET("/audit", handlers.GetAudit)
+	group.GET("/audit/summary", handlers.GetAuditSummary)
+
+	// Role endpoints
+	group.GET("/roles", handlers.GetRoles)
+	group.POST("/roles", handlers.GrantRole)
+	group.DELETE("/roles/:roleId", handlers.RevokeRole)
+
+	// API token endpoints
+	group.GET("/api-tokens", handlers.GetAPITokens)
+	group.POST("/api-tokens", handlers.CreateAPIToken)
+	group.DELETE("/api-tokens/:tokenId", handlers.RevokeAPIToken)
+}
@@ -0,0 +1,12 @@
+// Package db embeds the database changelog into the binary
+package db
+
+import "embed"
+
+// Changelog holds the liquibase formatted SQL migrations under changelog/master
+//
+//go:embed changelog/master/*.sql
+var Changelog embed.FS
+
+// ChangelogDir is the directory of the migrations inside Changelog
+const ChangelogDir = "changelog/master"
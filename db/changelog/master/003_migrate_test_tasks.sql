@@ -1,484 +0,0 @@
--- Migrate test tasks from React application
--- Version: 003
-
-DO $$
-DECLARE
-    test_team_id UUID;
-    test2_team_id UUID;
-BEGIN
-    -- Get team IDs
-    SELECT id INTO test_team_id FROM teams WHERE name = 'Test';
-    SELECT id INTO test2_team_id FROM teams WHERE name = 'Test 2';
-    
-    -- Clear existing test tasks
-    DELETE FROM tasks WHERE team_id IN (test_team_id, test2_team_id);
-    
-    -- Insert test tasks with proper UUIDs and correct data (now using string values for function and employee)
-    INSERT INTO tasks (
-        id, status, epic, task_name, team_id, function, employee,
-        plan_empl, plan_weeks, blocker_ids, week_blockers, fact, start_week, end_week,
-        expected_start_week, auto_plan_enabled, weeks
-    ) VALUES
-    -- Task 1
-    ('650e8400-e29b-41d4-a716-446655440001', 'Todo', '', 'Если не заданы доступные ресурсы, вовзращаем пустую строку', test_team_id, 'FN0', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, NULL, true, ARRAY[0,0,0,0,0,0,0,0,0]),
-    
-    -- Task 2
-    ('650e8400-e29b-41d4-a716-446655440002', 'Todo', '', 'Начинаем с той недели где заданы ресурсы', test_team_id, 'FN1', NULL,
-     1, 1, '{}', '{}', 0, NULL, NULL, 2, true, ARRAY[0,1,0,0,0,0,0,0,0]),
-    
-    -- Task 3
-    ('650e8400-e29b-41d4-a716-446655440003', 'Todo', '', 'Начинаем с той недели где заданы ресурсы и они не 0', test_team_id, 'FN2', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 2, true, ARRAY[0,1,1,0,0,0,0,0,0]),
-    
-    -- Task 4
-    ('650e8400-e29b-41d4-a716-446655440004', 'Todo', '', 'Начинаем с той недели, начиная с которой доступно необходимое кол-во недель для задачи', test_team_id, 'FN3', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 3, true, ARRAY[0,0,1,1,0,0,0,0,0]),
-    
-    -- Task 5
-    ('650e8400-e29b-41d4-a716-446655440005', 'Todo', '', 'Если ресурсы, заданы но их недостаточно возвращаем пустую строку', test_team_id, 'FN4', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, NULL, true, ARRAY[0,0,0,0,0,0,0,0,0]),
-    
-    -- Task 6
-    ('650e8400-e29b-41d4-a716-446655440006', 'Todo', '', 'Если ресурс занят другой задачей, планируем после нее', test_team_id, 'FN5', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, NULL, true, ARRAY[1,1,0,0,0,0,0,0,0]),
-    
-    -- Task 7
-    ('650e8400-e29b-41d4-a716-446655440007', 'Todo', '', 'Если ресурс занят другой задачей, планируем после нее', test_team_id, 'FN5', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 3, true, ARRAY[0,0,1,1,0,0,0,0,0]),
-    
-    -- Task 8
-    ('650e8400-e29b-41d4-a716-446655440008', 'Todo', '', 'Если ресурсы занят частично другой задачей и еще есть место планируем параллельно', test_team_id, 'FN6', NULL,
-     0, 0, '{}', '{}', 0, NULL, NULL, NULL, true, ARRAY[0,0,0,0,0,0,0,0,0]),
-    
-    -- Task 9
-    ('650e8400-e29b-41d4-a716-446655440009', 'Todo', '', 'Если ресурсы занят частично другой задачей и еще есть место планируем параллельно', test_team_id, 'FN6', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 1, true, ARRAY[1,1,0,0,0,0,0,0,0]),
-    
-    -- Task 10
-    ('650e8400-e29b-41d4-a716-44665544000a', 'Todo', '', 'Если задача требует нецелое число недель округляем в большую сторону', test_team_id, 'FN7', NULL,
-     1, 1.5, '{}', '{}', 0, NULL, NULL, 1, true, ARRAY[1,1,0,0,0,0,0,0,0]),
-    
-    -- Task 11
-    ('650e8400-e29b-41d4-a716-44665544000b', 'Todo', '', 'Если задача требует нецелое число недель округляем в большую сторону', test_team_id, 'FN7', NULL,
-     1, 1, '{}', '{}', 0, NULL, NULL, 3, true, ARRAY[0,0,1,0,0,0,0,0,0]),
-    
-    -- Task 12
-    ('650e8400-e29b-41d4-a716-44665544000c', 'Todo', '', 'Если у задачи указан блокер начинаем позже блокирующей недели', test_team_id, 'FN8', NULL,
-     1, 1, '{}', ARRAY[3], 0, NULL, NULL, 4, true, ARRAY[0,0,0,1,0,0,0,0,0]),
-    
-    -- Task 13
-    ('650e8400-e29b-41d4-a716-44665544000d', 'Todo', '', 'Если у задачи указан блокер и нет ресурсов сразу после блокера планируем не раньше блокирующей недели и не раньше чем появятся доступные ресурсы', test_team_id, 'FN8', NULL,
-     1, 1, '{}', ARRAY[3], 0, NULL, NULL, 6, true, ARRAY[0,0,0,0,0,1,0,0,0]),
-    
-    -- Task 14
-    ('650e8400-e29b-41d4-a716-44665544000e', 'Todo', '', 'Если одна функция определена для нескольких команд используем ресурсы той команды которая указана в задаче', test_team_id, 'FN9', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 1, true, ARRAY[1,1,0,0,0,0,0,0,0]),
-    
-    -- Task 15
-    ('650e8400-e29b-41d4-a716-44665544000f', 'Todo', '', 'Если одна функция определена для нескольких команд используем ресурсы той команды которая указана в задаче', test_team_id, 'FN9', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 3, true, ARRAY[0,0,1,1,0,0,0,0,0]),
-    
-    -- Task 16
-    ('650e8400-e29b-41d4-a716-446655440010', 'Todo', '', 'Если одна функция определена для нескольких команд используем ресурсы той команды которая указана в задаче', test2_team_id, 'FN9', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 1, true, ARRAY[1,1,0,0,0,0,0,0,0]),
-    
-    -- Task 17
-    ('650e8400-e29b-41d4-a716-446655440011', 'Todo', '', 'Если одна функция определена для нескольких команд используем ресурсы той команды которая указана в задаче', test2_team_id, 'FN9', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 3, true, ARRAY[0,0,1,1,0,0,0,0,0]),
-    
-    -- Task 18
-    ('650e8400-e29b-41d4-a716-446655440012', 'Todo', '', 'Если для одной функции задано сразу несколько команд используем ее ресурв в задачах обеих команд', test_team_id, 'FN10', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 1, true, ARRAY[1,1,0,0,0,0,0,0,0]),
-    
-    -- Task 19
-    ('650e8400-e29b-41d4-a716-446655440013', 'Todo', '', 'Если для одной функции задано сразу несколько команд используем ее ресурв в задачах обеих команд', test2_team_id, 'FN10', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 1, true, ARRAY[1,1,0,0,0,0,0,0,0]),
-    
-    -- Task 20
-    ('650e8400-e29b-41d4-a716-446655440014', 'Todo', '', 'Если для одной функции задано сразу несколько команд используем ее ресурв в задачах обеих команд', test_team_id, 'FN10', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 3, true, ARRAY[0,0,1,1,0,0,0,0,0]),
-    
-    -- Task 21
-    ('650e8400-e29b-41d4-a716-446655440015', 'Todo', '', 'Если для одной функции задано сразу несколько команд используем ее ресурв в задачах обеих команд', test2_team_id, 'FN10', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 3, true, ARRAY[0,0,1,1,0,0,0,0,0]),
-    
-    -- Task 22
-    ('650e8400-e29b-41d4-a716-446655440016', 'Todo', '', 'Две одинаковые функции, одна для одной команды, другая для двух', test2_team_id, 'FN11', NULL,
-     1, 1, '{}', '{}', 0, NULL, NULL, 1, true, ARRAY[1,0,0,0,0,0,0,0,0]),
-    
-    -- Task 23
-    ('650e8400-e29b-41d4-a716-446655440017', 'Todo', '', 'Две одинаковые функции, одна для одной команды, другая для двух', test_team_id, 'FN11', NULL,
-     1, 1, '{}', '{}', 0, NULL, NULL, 1, true, ARRAY[1,0,0,0,0,0,0,0,0]),
-    
-    -- Task 24
-    ('650e8400-e29b-41d4-a716-446655440018', 'Todo', '', 'Две одинаковые функции, одна для одной команды, другая для двух', test2_team_id, 'FN11', NULL,
-     1, 1, '{}', '{}', 0, NULL, NULL, 2, true, ARRAY[0,1,0,0,0,0,0,0,0]),
-    
-    -- Task 25
-    ('650e8400-e29b-41d4-a716-446655440019', 'Todo', '', 'Две одинаковые функции, одна для одной команды, другая для двух', test2_team_id, 'FN11', NULL,
-     1, 1, '{}', '{}', 0, NULL, NULL, 3, true, ARRAY[0,0,1,0,0,0,0,0,0]),
-    
-    -- Task 26
-    ('650e8400-e29b-41d4-a716-44665544001a', 'Todo', '', 'Две одинаковые функции, одна для одной команды, другая для двух', test_team_id, 'FN11', NULL,
-     1, 1, '{}', '{}', 0, NULL, NULL, 2, true, ARRAY[0,1,0,0,0,0,0,0,0]),
-    
-    -- Task 27
-    ('650e8400-e29b-41d4-a716-44665544001b', 'Todo', '', 'Две одинаковые функции, одна для одной команды, другая для двух', test2_team_id, 'FN11', NULL,
-     1, 1, '{}', '{}', 0, NULL, NULL, 4, true, ARRAY[0,0,0,1,0,0,0,0,0]),
-    
-    -- Task 28
-    ('650e8400-e29b-41d4-a716-44665544001c', 'Todo', '', 'Две одинаковые функции, одна для одной команды, другая для двух', test_team_id, 'FN11', NULL,
-     1, 1, '{}', '{}', 0, NULL, NULL, 3, true, ARRAY[0,0,1,0,0,0,0,0,0]),
-    
-    -- Task 29
-    ('650e8400-e29b-41d4-a716-44665544001d', 'Todo', '', 'Две одинаковые функции, одна для одной команды, другая для двух', test2_team_id, 'FN11', NULL,
-     1, 1, '{}', '{}', 0, NULL, NULL, 5, true, ARRAY[0,0,0,0,1,0,0,0,0]),
-    
-    -- Task 30
-    ('650e8400-e29b-41d4-a716-44665544001e', 'Todo', '', 'Две одинаковые функции, одна для одной команды, другая для двух', test2_team_id, 'FN11', NULL,
-     1, 1, '{}', '{}', 0, NULL, NULL, 6, true, ARRAY[0,0,0,0,0,1,0,0,0]),
-    
-    -- Task 31
-    ('650e8400-e29b-41d4-a716-44665544001f', 'Todo', '', 'Планирование общей задачи при наличии только персональных ресурсов, затем перс задачи', test_team_id, 'FN12', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 1, true, ARRAY[1,1,0,0,0,0,0,0,0]),
-    
-    -- Task 32
-    ('650e8400-e29b-41d4-a716-446655440020', 'Todo', '', 'Планирование общей задачи при наличии только персональных ресурсов, затем перс задачи', test_team_id, 'FN12', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 1, true, ARRAY[1,1,0,0,0,0,0,0,0]),
-    
-    -- Task 33
-    ('650e8400-e29b-41d4-a716-446655440021', 'Todo', '', 'Планирование персональной задачи после общих задач', test_team_id, 'FN12', 'Empl1',
-     1, 2, '{}', '{}', 0, NULL, NULL, 3, true, ARRAY[0,0,1,1,0,0,0,0,0]),
-    
-    -- Task 34
-    ('650e8400-e29b-41d4-a716-446655440022', 'Todo', '', 'Планирование персональной задачи после общих задач', test_team_id, 'FN12', 'Empl1',
-     1, 2, '{}', '{}', 0, NULL, NULL, 5, true, ARRAY[0,0,0,0,1,1,0,0,0]),
-    
-    -- Task 35
-    ('650e8400-e29b-41d4-a716-446655440023', 'Todo', '', 'Планирование персональной задачи после общих задач', test_team_id, 'FN12', 'Empl2',
-     1, 2, '{}', '{}', 0, NULL, NULL, 3, true, ARRAY[0,0,1,1,0,0,0,0,0]),
-    
-    -- Task 36
-    ('650e8400-e29b-41d4-a716-446655440024', 'Todo', '', 'Планирование персональной задачи после общих задач', test_team_id, 'FN12', 'Empl2',
-     1, 2, '{}', '{}', 0, NULL, NULL, 5, true, ARRAY[0,0,0,0,1,1,0,0,0]),
-    
-    -- Task 37
-    ('650e8400-e29b-41d4-a716-446655440025', 'Todo', '', 'Планирование персональной задачи', test_team_id, 'FN13', 'Empl1',
-     1, 2, '{}', '{}', 0, NULL, NULL, 1, true, ARRAY[1,1,0,0,0,0,0,0,0]),
-    
-    -- Task 38
-    ('650e8400-e29b-41d4-a716-446655440026', 'Todo', '', 'Планирование персональной задачи', test_team_id, 'FN13', 'Empl1',
-     1, 2, '{}', '{}', 0, NULL, NULL, 3, true, ARRAY[0,0,1,1,0,0,0,0,0]),
-    
-    -- Task 39
-    ('650e8400-e29b-41d4-a716-446655440027', 'Todo', '', 'Планирование персональной задачи', test_team_id, 'FN13', 'Empl2',
-     1, 2, '{}', '{}', 0, NULL, NULL, 1, true, ARRAY[1,1,0,0,0,0,0,0,0]),
-    
-    -- Task 40
-    ('650e8400-e29b-41d4-a716-446655440028', 'Todo', '', 'Планирование персональной задачи', test_team_id, 'FN13', 'Empl2',
-     1, 2, '{}', '{}', 0, NULL, NULL, 3, true, ARRAY[0,0,1,1,0,0,0,0,0]),
-
-    -- Task 41
-    ('650e8400-e29b-41d4-a716-446655440029', 'Todo', '', 'Планирование общей задачи при наличии только персональных ресурсов, после перс задач', test_team_id, 'FN13', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 5, true, ARRAY[0,0,0,0,1,1,0,0,0]),
-    
-    -- Task 42
-    ('650e8400-e29b-41d4-a716-44665544002a', 'Todo', '', 'Планирование общей задачи при наличии только персональных ресурсов, после перс задач', test_team_id, 'FN13', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 5, true, ARRAY[0,0,0,0,1,1,0,0,0]),
-    
-    -- Task 43
-    ('650e8400-e29b-41d4-a716-44665544002b', 'Todo', '', 'Два сотрудника одной функции работают на две команды (не важно кто делает, но будет делать Empl1 по приоритету ресурсов)', test_team_id, 'FN14', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 1, true, ARRAY[1,1,0,0,0,0,0,0,0]),
-    
-    -- Task 44
-    ('650e8400-e29b-41d4-a716-44665544002c', 'Todo', '', 'Два сотрудника одной функции работают на две команды (перс задача для Empl1)', test_team_id, 'FN14', 'Empl1',
-     1, 2, '{}', '{}', 0, NULL, NULL, 3, true, ARRAY[0,0,1,1,0,0,0,0,0]),
-    
-    -- Task 45
-    ('650e8400-e29b-41d4-a716-44665544002d', 'Todo', '', 'Два сотрудника одной функции работают на две команды (перс задача для Empl2)', test2_team_id, 'FN14', 'Empl2',
-     1, 2, '{}', '{}', 0, NULL, NULL, 1, true, ARRAY[1,1,0,0,0,0,0,0,0]),
-    
-    -- Task 46
-    ('650e8400-e29b-41d4-a716-44665544002e', 'Todo', '', 'Два сотрудника одной функции работают на две команды (перс задача для Empl2)', test2_team_id, 'FN14', 'Empl2',
-     1, 2, '{}', '{}', 0, NULL, NULL, 3, true, ARRAY[0,0,1,1,0,0,0,0,0]),
-    
-    -- Task 47
-    ('650e8400-e29b-41d4-a716-44665544002f', 'Todo', '', 'Два сотрудника одной функции работают на две команды (не важно кто делает, но будет делать Empl1 по приоритету ресурсов)', test_team_id, 'FN14', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 5, true, ARRAY[0,0,0,0,1,1,0,0,0]),
-    
-    -- Task 48
-    ('650e8400-e29b-41d4-a716-446655440030', 'Todo', '', 'Два сотрудника одной функции работают на две команды (не важно кто делает, но будет делать Empl2 по приоритету ресурсов)', test_team_id, 'FN14', NULL,
-     1, 2, '{}', '{}', 0, NULL, NULL, 5, true, ARRAY[0,0,0,0,1,1,0,0,0]),
-    
-    -- Task 49
-    ('650e8400-e29b-41d4-a716-446655440031', 'Todo', '', 'Два сотрудника одной функции работают на две команды (перс задача)', test_team_id, 'FN15', 'Empl1',
-     1, 1, '{}', '{}', 0, NULL, NULL, 1, true, ARRAY[1,0,0,0,0,0,0,0,0]),
-    
-    -- Task 50
-    ('650e8400-e29b-41d4-a716-446655440032', 'Todo', '', 'Два сотрудника одной функции работают на две команды (не важно кто делает, но будет делать Empl2 по приоритету ресурсов)', test_team_id, 'FN15', NULL,
-     1, 1, '{}', '{}', 0, NULL, NULL, 1, true, ARRAY[1,0,0,0,0,0,0,0,0]),
-    
-    -- Task 51
-    ('650e8400-e29b-41d4-a716-446655440033', 'Todo', '', 'Два сотрудника одной функции работают на две команды (не важно кто делает, но будет делать Empl1 по приоритету ресурсов)', test_team_id, 'FN15', NULL,
-     1, 1, '{}', '{}', 0, NULL, NULL, 2, true, ARRAY[0,2,0,0,0,0,0,0,0]);
-
-
-    -- Update linked list for tasks (prev_id/next_id instead of display_order)
-    -- Set prev_id and next_id to create linked list
-    UPDATE tasks SET 
-        prev_id = NULL, 
-        next_id = '650e8400-e29b-41d4-a716-446655440002' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440001';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440001', 
-        next_id = '650e8400-e29b-41d4-a716-446655440003' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440002';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440002', 
-        next_id = '650e8400-e29b-41d4-a716-446655440004' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440003';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440003', 
-        next_id = '650e8400-e29b-41d4-a716-446655440005' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440004';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440004', 
-        next_id = '650e8400-e29b-41d4-a716-446655440006' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440005';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440005', 
-        next_id = '650e8400-e29b-41d4-a716-446655440007' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440006';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440006', 
-        next_id = '650e8400-e29b-41d4-a716-446655440008' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440007';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440007', 
-        next_id = '650e8400-e29b-41d4-a716-446655440009' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440008';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440008', 
-        next_id = '650e8400-e29b-41d4-a716-44665544000a' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440009';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440009', 
-        next_id = '650e8400-e29b-41d4-a716-44665544000b' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544000a';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544000a', 
-        next_id = '650e8400-e29b-41d4-a716-44665544000c' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544000b';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544000b', 
-        next_id = '650e8400-e29b-41d4-a716-44665544000d' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544000c';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544000c', 
-        next_id = '650e8400-e29b-41d4-a716-44665544000e' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544000d';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544000d', 
-        next_id = '650e8400-e29b-41d4-a716-44665544000f' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544000e';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544000e', 
-        next_id = '650e8400-e29b-41d4-a716-446655440010' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544000f';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544000f', 
-        next_id = '650e8400-e29b-41d4-a716-446655440011' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440010';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440010', 
-        next_id = '650e8400-e29b-41d4-a716-446655440012' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440011';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440011', 
-        next_id = '650e8400-e29b-41d4-a716-446655440013' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440012';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440012', 
-        next_id = '650e8400-e29b-41d4-a716-446655440014' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440013';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440013', 
-        next_id = '650e8400-e29b-41d4-a716-446655440015' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440014';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440014', 
-        next_id = '650e8400-e29b-41d4-a716-446655440016' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440015';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440015', 
-        next_id = '650e8400-e29b-41d4-a716-446655440017' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440016';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440016', 
-        next_id = '650e8400-e29b-41d4-a716-446655440018' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440017';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440017', 
-        next_id = '650e8400-e29b-41d4-a716-446655440019' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440018';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440018', 
-        next_id = '650e8400-e29b-41d4-a716-44665544001a' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440019';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440019', 
-        next_id = '650e8400-e29b-41d4-a716-44665544001b' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544001a';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544001a', 
-        next_id = '650e8400-e29b-41d4-a716-44665544001c' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544001b';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544001b', 
-        next_id = '650e8400-e29b-41d4-a716-44665544001d' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544001c';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544001c', 
-        next_id = '650e8400-e29b-41d4-a716-44665544001e' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544001d';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544001d', 
-        next_id = '650e8400-e29b-41d4-a716-44665544001f' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544001e';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544001e', 
-        next_id = '650e8400-e29b-41d4-a716-446655440020' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544001f';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544001f', 
-        next_id = '650e8400-e29b-41d4-a716-446655440021' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440020';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440020', 
-        next_id = '650e8400-e29b-41d4-a716-446655440022' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440021';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440021', 
-        next_id = '650e8400-e29b-41d4-a716-446655440023' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440022';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440022', 
-        next_id = '650e8400-e29b-41d4-a716-446655440024' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440023';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440023', 
-        next_id = '650e8400-e29b-41d4-a716-446655440025' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440024';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440024', 
-        next_id = '650e8400-e29b-41d4-a716-446655440026' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440025';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440025', 
-        next_id = '650e8400-e29b-41d4-a716-446655440027' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440026';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440026', 
-        next_id = '650e8400-e29b-41d4-a716-446655440028' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440027';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440027', 
-        next_id = '650e8400-e29b-41d4-a716-446655440029' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440028';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440028', 
-        next_id = '650e8400-e29b-41d4-a716-44665544002a' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440029';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440029', 
-        next_id = '650e8400-e29b-41d4-a716-44665544002b' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544002a';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544002a', 
-        next_id = '650e8400-e29b-41d4-a716-44665544002c' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544002b';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544002b', 
-        next_id = '650e8400-e29b-41d4-a716-44665544002d' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544002c';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544002c', 
-        next_id = '650e8400-e29b-41d4-a716-44665544002e' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544002d';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544002d', 
-        next_id = '650e8400-e29b-41d4-a716-44665544002f' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544002e';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544002e', 
-        next_id = '650e8400-e29b-41d4-a716-446655440030' 
-    WHERE id = '650e8400-e29b-41d4-a716-44665544002f';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-44665544002f', 
-        next_id = '650e8400-e29b-41d4-a716-446655440031' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440030';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440030', 
-        next_id = '650e8400-e29b-41d4-a716-446655440032' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440031';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440031', 
-        next_id = '650e8400-e29b-41d4-a716-446655440033' 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440032';
-    
-    UPDATE tasks SET 
-        prev_id = '650e8400-e29b-41d4-a716-446655440032', 
-        next_id = NULL 
-    WHERE id = '650e8400-e29b-41d4-a716-446655440033';
-
-END $$;
@@ -0,0 +1,82 @@
+--liquibase formatted sql
+
+--changeset dvdoroginin:006_roadmap_documents
+--comment: Support multiple independent roadmap documents
+
+-- Every row of document_versions is now a separate roadmap document
+ALTER TABLE document_versions ADD COLUMN name VARCHAR(255);
+UPDATE document_versions SET name = 'Roadmap' WHERE name IS NULL;
+ALTER TABLE document_versions ALTER COLUMN name SET NOT NULL;
+
+-- Attach all data to the existing (default) document
+ALTER TABLE teams ADD COLUMN document_id UUID REFERENCES document_versions(id);
+ALTER TABLE sprints ADD COLUMN document_id UUID REFERENCES document_versions(id);
+ALTER TABLE resources ADD COLUMN document_id UUID REFERENCES document_versions(id);
+ALTER TABLE tasks ADD COLUMN document_id UUID REFERENCES document_versions(id);
+ALTER TABLE change_log ADD COLUMN document_id UUID REFERENCES document_versions(id);
+
+-- Backfill silently: it is not a data change and must not bump versions
+ALTER TABLE teams DISABLE TRIGGER USER;
+ALTER TABLE sprints DISABLE TRIGGER USER;
+ALTER TABLE resources DISABLE TRIGGER USER;
+ALTER TABLE tasks DISABLE TRIGGER USER;
+
+UPDATE teams SET document_id = (SELECT id FROM document_versions ORDER BY created_at LIMIT 1);
+UPDATE sprints SET document_id = (SELECT id FROM document_versions ORDER BY created_at LIMIT 1);
+UPDATE resources SET document_id = (SELECT id FROM document_versions ORDER BY created_at LIMIT 1);
+UPDATE tasks SET document_id = (SELECT id FROM document_versions ORDER BY created_at LIMIT 1);
+UPDATE change_log SET document_id = (SELECT id FROM document_versions ORDER BY created_at LIMIT 1);
+
+ALTER TABLE teams ENABLE TRIGGER USER;
+ALTER TABLE sprints ENABLE TRIGGER USER;
+ALTER TABLE resources ENABLE TRIGGER USER;
+ALTER TABLE tasks ENABLE TRIGGER USER;
+
+ALTER TABLE teams ALTER COLUMN document_id SET NOT NULL;
+ALTER TABLE sprints ALTER COLUMN document_id SET NOT NULL;
+ALTER TABLE resources ALTER COLUMN document_id SET NOT NULL;
+ALTER TABLE tasks ALTER COLUMN document_id SET NOT NULL;
+ALTER TABLE change_log ALTER COLUMN document_id SET NOT NULL;
+
+-- Team names and sprint codes are unique within a document
+ALTER TABLE teams DROP CONSTRAINT teams_name_key;
+ALTER TABLE teams ADD CONSTRAINT teams_document_name_key UNIQUE (document_id, name);
+ALTER TABLE sprints DROP CONSTRAINT sprints_code_key;
+ALTER TABLE sprints ADD CONSTRAINT sprints_document_code_key UNIQUE (document_id, code);
+
+CREATE INDEX idx_teams_document_id ON teams (document_id);
+CREATE INDEX idx_sprints_document_id ON sprints (document_id);
+CREATE INDEX idx_resources_document_id ON resources (document_id);
+CREATE INDEX idx_tasks_document_id ON tasks (document_id);
+CREATE INDEX idx_change_log_document_version ON change_log (document_id, version_number);
+
+-- Version counter and change log are scoped to the document of the changed row
+CREATE OR REPLACE FUNCTION log_data_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    new_version BIGINT;
+    doc_id UUID;
+BEGIN
+    doc_id := CASE WHEN TG_OP = 'DELETE' THEN OLD.document_id ELSE NEW.document_id END;
+
+    -- Increment version number of the document
+    UPDATE document_versions
+    SET version_number = version_number + 1
+    WHERE id = doc_id
+    RETURNING version_number INTO STRICT new_version;
+
+    -- Log the change
+    INSERT INTO change_log (document_id, version_number, table_name, record_id, operation, old_data, new_data)
+    VALUES (
+        doc_id,
+        new_version,
+        TG_TABLE_NAME,
+        COALESCE(NEW.id, OLD.id),
+        TG_OP,
+        CASE WHEN TG_OP = 'DELETE' THEN to_jsonb(OLD) ELSE NULL END,
+        CASE WHEN TG_OP = 'INSERT' OR TG_OP = 'UPDATE' THEN to_jsonb(NEW) ELSE NULL END
+    );
+
+    RETURN COALESCE(NEW, OLD);
+END;
+$$ language 'plpgsql';
@@ -0,0 +1,21 @@
+--liquibase formatted sql
+
+--changeset dvdoroginin:007_scenarios
+--comment: What-if scenario branches stored as overrides on top of a base version
+
+CREATE TABLE scenarios (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    document_id UUID NOT NULL REFERENCES document_versions(id),
+    name VARCHAR(255) NOT NULL,
+    base_version BIGINT NOT NULL, -- document version the scenario was forked from
+    version BIGINT NOT NULL DEFAULT 1, -- scenario's own counter for optimistic locking
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, merged, discarded
+    changes JSONB NOT NULL DEFAULT '{}', -- accumulated UpdateRequest-shaped overrides
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_scenarios_document_id ON scenarios (document_id);
+
+CREATE TRIGGER update_scenarios_updated_at BEFORE UPDATE ON scenarios FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,16 @@
+--liquibase formatted sql
+
+--changeset dvdoroginin:008_baselines
+--comment: Named baselines freezing the plan at a document version
+
+CREATE TABLE baselines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    document_id UUID NOT NULL REFERENCES document_versions(id),
+    name VARCHAR(255) NOT NULL,
+    version BIGINT NOT NULL, -- document version the plan is reconstructed at from change_log
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_baselines_document_id ON baselines (document_id);
+
@@ -0,0 +1,18 @@
+--liquibase formatted sql
+
+--changeset dvdoroginin:009_change_log_compaction
+--comment: Snapshots and retention window for change_log compaction
+
+-- change_log keeps every change after this version; older ones are folded into a snapshot
+ALTER TABLE document_versions ADD COLUMN log_start_version BIGINT NOT NULL DEFAULT 0;
+
+-- Full document state at a version in the change_log row format: {"tasks": {"<id>": {...}}, ...}
+CREATE TABLE document_snapshots (
+    document_id UUID NOT NULL REFERENCES document_versions(id),
+    version BIGINT NOT NULL,
+    data JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (document_id, version)
+);
+
+CREATE INDEX idx_change_log_document_created_at ON change_log (document_id, created_at);
@@ -0,0 +1,56 @@
+--liquibase formatted sql
+
+--changeset dvdoroginin:010_change_sets
+--comment: Restore user_id attribution lost in 005 and record request metadata per change set
+
+-- One row per UpdateData transaction
+CREATE TABLE change_sets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    document_id UUID NOT NULL REFERENCES document_versions(id),
+    user_id VARCHAR(255),
+    request_id VARCHAR(255),
+    client_ip VARCHAR(64),
+    user_agent TEXT,
+    note TEXT, -- annotation, e.g. for changes whose author was not recorded
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_change_sets_document_id ON change_sets (document_id, created_at);
+
+ALTER TABLE change_log ADD COLUMN change_set_id UUID REFERENCES change_sets(id);
+
+CREATE INDEX idx_change_log_change_set_id ON change_log (change_set_id);
+
+-- Same as 006 plus user_id (lost in 005) and change_set_id from session variables
+-- set by Repository.UpdateData
+CREATE OR REPLACE FUNCTION log_data_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    new_version BIGINT;
+    doc_id UUID;
+BEGIN
+    doc_id := CASE WHEN TG_OP = 'DELETE' THEN OLD.document_id ELSE NEW.document_id END;
+
+    -- Increment version number of the document
+    UPDATE document_versions
+    SET version_number = version_number + 1
+    WHERE id = doc_id
+    RETURNING version_number INTO STRICT new_version;
+
+    -- Log the change
+    INSERT INTO change_log (document_id, version_number, table_name, record_id, operation, user_id, change_set_id, old_data, new_data)
+    VALUES (
+        doc_id,
+        new_version,
+        TG_TABLE_NAME,
+        COALESCE(NEW.id, OLD.id),
+        TG_OP,
+        NULLIF(current_setting('app.user_id', true), ''),
+        NULLIF(current_setting('app.change_set_id', true), '')::UUID,
+        CASE WHEN TG_OP = 'DELETE' THEN to_jsonb(OLD) ELSE NULL END,
+        CASE WHEN TG_OP = 'INSERT' OR TG_OP = 'UPDATE' THEN to_jsonb(NEW) ELSE NULL END
+    );
+
+    RETURN COALESCE(NEW, OLD);
+END;
+$$ language 'plpgsql';
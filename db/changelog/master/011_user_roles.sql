@@ -0,0 +1,18 @@
+--liquibase formatted sql
+
+--changeset dvdoroginin:011_user_roles
+--comment: Per-team roles of users in a roadmap
+
+CREATE TABLE user_roles (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    document_id UUID NOT NULL REFERENCES document_versions(id),
+    user_id VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('viewer', 'planner', 'teamLead', 'admin')),
+    team_id UUID REFERENCES teams(id) ON DELETE CASCADE, -- NULL grants the role for all teams
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- One role per user and team (or roadmap-wide)
+CREATE UNIQUE INDEX idx_user_roles_user_team
+    ON user_roles (document_id, user_id, COALESCE(team_id, '00000000-0000-0000-0000-000000000000'::uuid));
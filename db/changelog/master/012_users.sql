@@ -0,0 +1,13 @@
+--liquibase formatted sql
+
+--changeset dvdoroginin:012_users
+--comment: User directory with display info for change attribution
+
+CREATE TABLE users (
+    id VARCHAR(255) PRIMARY KEY, -- same id as change_log.user_id
+    display_name VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    avatar_color VARCHAR(7) NOT NULL, -- #RRGGBB
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
@@ -0,0 +1,26 @@
+--liquibase formatted sql
+
+--changeset dvdoroginin:013_api_tokens
+--comment: API tokens for automation clients acting as service identities
+
+CREATE TABLE api_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    document_id UUID NOT NULL REFERENCES document_versions(id),
+    name VARCHAR(255) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE, -- hex SHA-256 of the secret; the secret itself is never stored
+    token_prefix VARCHAR(16) NOT NULL,   -- start of the secret to recognize the token in lists
+    scope VARCHAR(10) NOT NULL CHECK (scope IN ('read', 'write')),
+    role VARCHAR(20) CHECK (role IN ('planner', 'teamLead', 'admin')), -- role of write tokens
+    team_ids UUID[],                     -- NULL: all teams
+    service_user_id VARCHAR(255) NOT NULL, -- user id recorded in change_log for changes made with the token
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_api_tokens_document_id ON api_tokens (document_id);
+
+-- Token a change set was made with
+ALTER TABLE change_sets ADD COLUMN api_token_id UUID REFERENCES api_tokens(id);
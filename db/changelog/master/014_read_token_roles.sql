@@ -0,0 +1,12 @@
+--liquibase formatted sql
+
+--changeset dvdoroginin:014_read_token_roles
+--comment: Viewer roles for the service users of read API tokens, now that reads check roles
+
+INSERT INTO user_roles (document_id, user_id, role, team_id, created_by)
+SELECT t.document_id, t.service_user_id, 'viewer', team_id, t.created_by
+FROM api_tokens t
+LEFT JOIN LATERAL unnest(t.team_ids) AS team_id ON TRUE
+WHERE t.scope = 'read' AND t.revoked_at IS NULL
+    AND (team_id IS NULL OR EXISTS (SELECT 1 FROM teams WHERE id = team_id))
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+--liquibase formatted sql
+
+--changeset dvdoroginin:015_change_log_record_version
+--comment: Lookup of the previous logged state of a record for the audit log
+
+CREATE INDEX idx_change_log_record_version ON change_log (table_name, record_id, version_number);
@@ -0,0 +1,13 @@
+--liquibase formatted sql
+
+--changeset dvdoroginin:016_revoke_team_read_tokens
+--comment: Read tokens limited to teams could read the whole roadmap; such tokens are no longer issued
+
+DELETE FROM user_roles
+WHERE user_id IN (
+    SELECT service_user_id FROM api_tokens
+    WHERE scope = 'read' AND revoked_at IS NULL AND cardinality(team_ids) > 0
+);
+
+UPDATE api_tokens SET revoked_at = NOW()
+WHERE scope = 'read' AND revoked_at IS NULL AND cardinality(team_ids) > 0;
@@ -0,0 +1,37 @@
+--liquibase formatted sql
+
+--changeset dvdoroginin:017_log_update_old_data
+--comment: Log the previous state of updated rows, so that the state before a change does not depend on earlier log entries
+
+-- Same as 010 with old_data for UPDATE
+CREATE OR REPLACE FUNCTION log_data_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    new_version BIGINT;
+    doc_id UUID;
+BEGIN
+    doc_id := CASE WHEN TG_OP = 'DELETE' THEN OLD.document_id ELSE NEW.document_id END;
+
+    -- Increment version number of the document
+    UPDATE document_versions
+    SET version_number = version_number + 1
+    WHERE id = doc_id
+    RETURNING version_number INTO STRICT new_version;
+
+    -- Log the change
+    INSERT INTO change_log (document_id, version_number, table_name, record_id, operation, user_id, change_set_id, old_data, new_data)
+    VALUES (
+        doc_id,
+        new_version,
+        TG_TABLE_NAME,
+        COALESCE(NEW.id, OLD.id),
+        TG_OP,
+        NULLIF(current_setting('app.user_id', true), ''),
+        NULLIF(current_setting('app.change_set_id', true), '')::UUID,
+        CASE WHEN TG_OP = 'DELETE' OR TG_OP = 'UPDATE' THEN to_jsonb(OLD) ELSE NULL END,
+        CASE WHEN TG_OP = 'INSERT' OR TG_OP = 'UPDATE' THEN to_jsonb(NEW) ELSE NULL END
+    );
+
+    RETURN COALESCE(NEW, OLD);
+END;
+$$ language 'plpgsql';
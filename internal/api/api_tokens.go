@@ -0,0 +1,96 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+	"roadmap/internal/service"
+)
+
+// GetAPITokens returns the API tokens of the roadmap without their secrets
+func (h *Handlers) GetAPITokens(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	tokens, err := h.service.GetAPITokens(documentID, currentUserID(c))
+	if err != nil {
+		writeAPITokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// CreateAPIToken creates an API token; the secret is only in this response
+func (h *Handlers) CreateAPIToken(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	token, err := h.service.CreateAPIToken(documentID, currentUserID(c), &req)
+	if err != nil {
+		writeAPITokenError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+// RevokeAPIToken revokes an API token
+func (h *Handlers) RevokeAPIToken(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("tokenId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid token id",
+		})
+		return
+	}
+
+	if err := h.service.RevokeAPIToken(documentID, currentUserID(c), id); err != nil {
+		writeAPITokenError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func writeAPITokenError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidToken), errors.Is(err, service.ErrInvalidRole):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Internal server error: " + err.Error(),
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+	"roadmap/internal/service"
+)
+
+// ExportArchive returns a JSON backup of the roadmap as a file download;
+// ?includeChangeLog=true adds the retained change log
+func (h *Handlers) ExportArchive(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+	includeChangeLog, ok := boolQuery(c, "includeChangeLog")
+	if !ok {
+		return
+	}
+
+	archive, err := h.service.ExportArchive(documentID, includeChangeLog)
+	if err != nil {
+		writeArchiveError(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("roadmap-%s-v%d.json", documentID, archive.Roadmap.Version)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.JSON(http.StatusOK, archive)
+}
+
+// ImportArchive restores an archive written by ExportArchive into the roadmap.
+// The roadmap must be empty unless ?replace=true; ?remapIds=true gives every
+// row a new id. Without authentication the author is taken from ?userId.
+func (h *Handlers) ImportArchive(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	var archive models.Archive
+	if err := c.ShouldBindJSON(&archive); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	opts := models.ImportOptions{UserID: c.Query("userId")}
+	if opts.Replace, ok = boolQuery(c, "replace"); !ok {
+		return
+	}
+	if opts.RemapIDs, ok = boolQuery(c, "remapIds"); !ok {
+		return
+	}
+	if !authenticatedUser(c, &opts.UserID) {
+		return
+	}
+	if _, err := uuid.Parse(opts.UserID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid UserID format: must be a valid UUID",
+		})
+		return
+	}
+
+	opts.Meta = changeSetMeta(c)
+	result, err := h.service.ImportArchive(documentID, &archive, &opts)
+	if err != nil {
+		writeArchiveError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// boolQuery parses an optional boolean query parameter. It writes the error
+// response itself and returns false on failure.
+func boolQuery(c *gin.Context, name string) (bool, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return false, true
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Invalid %s parameter: must be true or false", name),
+		})
+		return false, false
+	}
+	return parsed, true
+}
+
+func writeArchiveError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidArchive):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrRoadmapNotEmpty), errors.Is(err, service.ErrIDConflict):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Internal server error: " + err.Error(),
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+	"roadmap/internal/service"
+)
+
+// Page size bounds of GET /audit
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// GetAudit returns change_log entries filtered by ?userId, table, operation,
+// recordId, field and the from/to time range, newest first
+func (h *Handlers) GetAudit(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+	filter, ok := auditFilter(c)
+	if !ok {
+		return
+	}
+
+	filter.Limit = defaultAuditLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxAuditLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Invalid limit parameter: must be between 1 and %d", maxAuditLimit),
+			})
+			return
+		}
+		filter.Limit = limit
+	}
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		cursor, err := strconv.ParseInt(cursorStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid cursor parameter",
+			})
+			return
+		}
+		filter.Cursor = &cursor
+	}
+
+	audit, err := h.service.GetAuditLog(documentID, filter)
+	if err != nil {
+		writeAuditError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, audit)
+}
+
+// GetAuditSummary returns the number of changes per user per day for the same
+// filters as GetAudit
+func (h *Handlers) GetAuditSummary(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+	filter, ok := auditFilter(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.service.GetAuditSummary(documentID, filter)
+	if err != nil {
+		writeAuditError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// auditFilter parses the audit query parameters; from and to accept RFC 3339
+// timestamps or dates (YYYY-MM-DD, UTC midnight)
+func auditFilter(c *gin.Context) (*models.AuditFilter, bool) {
+	filter := &models.AuditFilter{
+		UserID:    c.Query("userId"),
+		Table:     c.Query("table"),
+		Operation: c.Query("operation"),
+		Column:    c.Query("field"),
+	}
+
+	if recordIDStr := c.Query("recordId"); recordIDStr != "" {
+		recordID, err := uuid.Parse(recordIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid recordId parameter",
+			})
+			return nil, false
+		}
+		filter.RecordID = &recordID
+	}
+
+	for name, dst := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t, err = time.Parse(time.DateOnly, value)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Invalid %s parameter: expected RFC 3339 time or YYYY-MM-DD", name),
+			})
+			return nil, false
+		}
+		*dst = &t
+	}
+
+	return filter, true
+}
+
+func writeAuditError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrInvalidFilter) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": "Failed to get audit log",
+	})
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+	"roadmap/internal/service"
+)
+
+// GetBaselines returns all baselines of the roadmap
+func (h *Handlers) GetBaselines(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	baselines, err := h.service.GetBaselines(documentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get baselines",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, baselines)
+}
+
+// GetBaseline returns a baseline description
+func (h *Handlers) GetBaseline(c *gin.Context) {
+	documentID, baselineID, ok := h.baselineID(c)
+	if !ok {
+		return
+	}
+
+	baseline, err := h.service.GetBaseline(documentID, baselineID)
+	if err != nil {
+		writeBaselineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, baseline)
+}
+
+// CreateBaseline freezes the plan at a version under a name
+func (h *Handlers) CreateBaseline(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateBaselineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Name is required",
+		})
+		return
+	}
+	if !authenticatedUser(c, &req.UserID) {
+		return
+	}
+	if _, err := uuid.Parse(req.UserID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid UserID format: must be a valid UUID",
+		})
+		return
+	}
+
+	baseline, err := h.service.CreateBaseline(documentID, &req)
+	if err != nil {
+		writeBaselineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, baseline)
+}
+
+// DeleteBaseline removes a baseline
+func (h *Handlers) DeleteBaseline(c *gin.Context) {
+	documentID, baselineID, ok := h.baselineID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteBaseline(documentID, currentUserID(c), baselineID); err != nil {
+		writeBaselineError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetBaselineData returns the plan as it was at the baseline version
+func (h *Handlers) GetBaselineData(c *gin.Context) {
+	documentID, baselineID, ok := h.baselineID(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.service.GetBaselineData(documentID, baselineID)
+	if err != nil {
+		writeBaselineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, data)
+}
+
+// GetBaselineReport compares the current plan with the baseline
+func (h *Handlers) GetBaselineReport(c *gin.Context) {
+	documentID, baselineID, ok := h.baselineID(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.GetBaselineReport(documentID, baselineID)
+	if err != nil {
+		writeBaselineError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// baselineID resolves the document and the :baselineId route parameter
+func (h *Handlers) baselineID(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	baselineID, err := uuid.Parse(c.Param("baselineId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid baseline id",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return documentID, baselineID, true
+}
+
+func writeBaselineError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Baseline not found",
+		})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidVersion):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrVersionPruned):
+		c.JSON(http.StatusGone, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Internal server error: " + err.Error(),
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+	"roadmap/internal/service"
+)
+
+// DuplicateTask duplicates a task (or its whole epic) below the original
+func (h *Handlers) DuplicateTask(c *gin.Context) {
+	h.duplicate(c, h.service.DuplicateTask)
+}
+
+// DuplicateResource duplicates a resource row below the original
+func (h *Handlers) DuplicateResource(c *gin.Context) {
+	h.duplicate(c, h.service.DuplicateResource)
+}
+
+func (h *Handlers) duplicate(c *gin.Context, duplicate func(uuid.UUID, uuid.UUID, *models.DuplicateRequest) (*models.DuplicateResponse, error)) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid id parameter",
+		})
+		return
+	}
+
+	var req models.DuplicateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	if !authenticatedUser(c, &req.UserID) {
+		return
+	}
+	if _, err := uuid.Parse(req.UserID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid UserID format: must be a valid UUID",
+		})
+		return
+	}
+
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	req.Meta = changeSetMeta(c)
+	response, err := duplicate(documentID, id, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Internal server error: " + err.Error(),
+		})
+		return
+	}
+
+	if !response.Success {
+		if len(response.Forbidden) > 0 {
+			c.JSON(http.StatusForbidden, response)
+			return
+		}
+		if strings.HasPrefix(response.Error, "Version conflict") {
+			c.JSON(http.StatusConflict, response)
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"roadmap/internal/export"
+	"roadmap/internal/models"
+)
+
+// ExportCSV returns the plan grid as a CSV file: one row per resource and
+// task with the plan screen columns, then one column per week
+func (h *Handlers) ExportCSV(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.service.GetAllData(documentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get data",
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("roadmap-%s-v%d.csv", documentID, data.Version)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := export.WriteCSV(c.Writer, export.NewGrid(data)); err != nil {
+		// The status is sent already; the client sees a truncated file
+		log.Printf("Failed to write CSV export of roadmap %s: %v", documentID, err)
+	}
+}
+
+// ExportXLSX returns the plan grid as an Excel workbook formatted like the
+// plan screen
+func (h *Handlers) ExportXLSX(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	data, err := h.service.GetAllData(documentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get data",
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("roadmap-%s-v%d.xlsx", documentID, data.Version)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Status(http.StatusOK)
+	if err := export.WriteXLSX(c.Writer, export.NewGrid(data)); err != nil {
+		log.Printf("Failed to write XLSX export of roadmap %s: %v", documentID, err)
+	}
+}
+
+// ExportMermaid returns the tasks as a Mermaid gantt chart for Markdown documents
+func (h *Handlers) ExportMermaid(c *gin.Context) {
+	h.exportDiagram(c, "mmd", export.WriteMermaid)
+}
+
+// ExportPlantUML returns the tasks as a PlantUML Gantt diagram
+func (h *Handlers) ExportPlantUML(c *gin.Context) {
+	h.exportDiagram(c, "puml", export.WritePlantUML)
+}
+
+// exportDiagram writes a text Gantt chart of the tasks selected by the ?epic=
+// and ?team= parameters, titled with the roadmap name
+func (h *Handlers) exportDiagram(c *gin.Context, extension string,
+	write func(io.Writer, *models.DataResponse, string, export.DiagramFilter) error) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	roadmap, err := h.service.GetRoadmap(documentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get roadmap",
+		})
+		return
+	}
+	data, err := h.service.GetAllData(documentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get data",
+		})
+		return
+	}
+
+	filter := export.DiagramFilter{Epic: c.Query("epic"), Team: c.Query("team")}
+	var text bytes.Buffer
+	if err := write(&text, data, roadmap.Name, filter); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to export diagram",
+		})
+		return
+	}
+	filename := fmt.Sprintf("roadmap-%s-v%d.%s", documentID, data.Version, extension)
+	c.Header("Content-Disposition", `inline; filename="`+filename+`"`)
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", text.Bytes())
+}
+
+// GetCalendar returns an iCalendar feed with sprint boundaries and task start
+// and end dates for calendar subscriptions, optionally of one ?team= or
+// ?employee=
+func (h *Handlers) GetCalendar(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	roadmap, err := h.service.GetRoadmap(documentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get roadmap",
+		})
+		return
+	}
+	data, err := h.service.GetAllData(documentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get data",
+		})
+		return
+	}
+
+	filter := export.CalendarFilter{Team: c.Query("team"), Employee: c.Query("employee")}
+	var feed bytes.Buffer
+	if err := export.WriteICS(&feed, data, roadmap.Name, filter); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to export calendar",
+		})
+		return
+	}
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", feed.Bytes())
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -9,21 +10,105 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"roadmap/internal/auth"
+	"roadmap/internal/export"
 	"roadmap/internal/models"
 	"roadmap/internal/service"
 )
 
 type Handlers struct {
 	service *service.Service
+	pdfFont *export.Font // Font embedded into PDF renderings; nil disables them
 }
 
 func New(service *service.Service) *Handlers {
 	return &Handlers{service: service}
 }
 
+// EnablePDF enables PDF renderings with the font
+func (h *Handlers) EnablePDF(font *export.Font) {
+	h.pdfFont = font
+}
+
+// documentID resolves the roadmap document addressed by the request: the
+// :roadmapId route parameter, or the default document for unscoped routes,
+// and checks that the user may read it. It writes the error response itself
+// and returns false on failure.
+func (h *Handlers) documentID(c *gin.Context) (uuid.UUID, bool) {
+	id, ok := h.resolveDocumentID(c)
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	// API tokens only give access to their own roadmap
+	if value, exists := c.Get(identityKey); exists {
+		if scope := value.(*auth.Identity).DocumentID; scope != nil && *scope != id {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "The API token does not give access to this roadmap",
+			})
+			return uuid.Nil, false
+		}
+	}
+
+	if err := h.service.RequireReadAccess(id, currentUserID(c)); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+			})
+			return uuid.Nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get roles",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// resolveDocumentID resolves the :roadmapId route parameter or the default roadmap
+func (h *Handlers) resolveDocumentID(c *gin.Context) (uuid.UUID, bool) {
+	param := c.Param("roadmapId")
+	if param == "" {
+		id, err := h.service.GetDefaultDocumentID()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to get default roadmap",
+			})
+			return uuid.Nil, false
+		}
+		return id, true
+	}
+
+	id, err := uuid.Parse(param)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid roadmap id",
+		})
+		return uuid.Nil, false
+	}
+	if _, err := h.service.GetRoadmap(id); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Roadmap not found",
+			})
+			return uuid.Nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get roadmap",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
 // GetVersion returns the current document version (lightweight endpoint)
 func (h *Handlers) GetVersion(c *gin.Context) {
-	version, err := h.service.GetCurrentVersion()
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	version, err := h.service.GetCurrentVersion(documentID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to get current version",
@@ -36,7 +121,38 @@ func (h *Handlers) GetVersion(c *gin.Context) {
 
 // GetData returns all data with the current version
 func (h *Handlers) GetData(c *gin.Context) {
-	data, err := h.service.GetAllData()
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	// ?scenario=<id> returns the plan as seen through a what-if scenario
+	if scenarioParam := c.Query("scenario"); scenarioParam != "" {
+		scenarioID, err := uuid.Parse(scenarioParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid scenario parameter",
+			})
+			return
+		}
+		data, err := h.service.GetScenarioData(documentID, scenarioID)
+		if err != nil {
+			if errors.Is(err, service.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Scenario not found",
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to get scenario data",
+			})
+			return
+		}
+		c.JSON(http.StatusOK, data)
+		return
+	}
+
+	data, err := h.service.GetAllData(documentID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to get data",
@@ -47,7 +163,13 @@ func (h *Handlers) GetData(c *gin.Context) {
 	c.JSON(http.StatusOK, data)
 }
 
-// GetDataDiff returns changes since the specified version
+// Page size bounds of GET /data/diff/:fromVersion
+const (
+	defaultDiffLimit = 500
+	maxDiffLimit     = 5000
+)
+
+// GetDataDiff returns changes since the specified version page by page
 func (h *Handlers) GetDataDiff(c *gin.Context) {
 	fromVersionStr := c.Param("fromVersion")
 	fromVersion, err := strconv.ParseInt(fromVersionStr, 10, 64)
@@ -58,8 +180,100 @@ func (h *Handlers) GetDataDiff(c *gin.Context) {
 		return
 	}
 
-	diff, err := h.service.GetDataDiff(fromVersion)
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	limit := defaultDiffLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxDiffLimit {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Invalid limit parameter: must be between 1 and %d", maxDiffLimit),
+			})
+			return
+		}
+	}
+
+	var cursor *int64
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		value, err := strconv.ParseInt(cursorStr, 10, 64)
+		if err != nil || value < fromVersion {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid cursor parameter",
+			})
+			return
+		}
+		cursor = &value
+	}
+
+	diff, err := h.service.GetDataDiff(documentID, fromVersion, cursor, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get data diff",
+		})
+		return
+	}
+
+	// The version predates the retained change log: the client must reload /data
+	if diff.ResyncRequired {
+		c.JSON(http.StatusGone, diff)
+		return
+	}
+	// The diff is larger than the document: the client should reload /data.
+	// A non-2xx status keeps clients unaware of the flag from skipping changes.
+	if diff.FetchFullData {
+		c.JSON(http.StatusConflict, diff)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// GetVersionDiff returns net field-level changes between versions ?from=A&to=B.
+// to defaults to the current version.
+func (h *Handlers) GetVersionDiff(c *gin.Context) {
+	fromVersion, err := strconv.ParseInt(c.Query("from"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid from parameter",
+		})
+		return
+	}
+
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	var toVersion *int64
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := strconv.ParseInt(toStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid to parameter",
+			})
+			return
+		}
+		toVersion = &to
+	}
+
+	diff, err := h.service.GetVersionDiff(documentID, fromVersion, toVersion)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidVersion) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrVersionPruned) {
+			c.JSON(http.StatusGone, gin.H{
+				"error":          err.Error(),
+				"resyncRequired": true,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to get data diff",
 		})
@@ -86,6 +300,10 @@ func (h *Handlers) UpdateData(c *gin.Context) {
 	fmt.Printf("UpdateData: JSON binding successful\n")
 	fmt.Fprintf(os.Stderr, "UpdateData: JSON binding successful\n")
 
+	if !authenticatedUser(c, &req.UserID) {
+		return
+	}
+
 	// Validate required UserID
 	if req.UserID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -118,7 +336,13 @@ func (h *Handlers) UpdateData(c *gin.Context) {
 	fmt.Printf("UpdateData: Valid changes found, proceeding with update\n")
 	fmt.Printf("UpdateData: Calling service.UpdateData\n")
 
-	response, err := h.service.UpdateData(&req)
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	req.Meta = changeSetMeta(c)
+	response, err := h.service.UpdateData(documentID, &req)
 	if err != nil {
 		fmt.Printf("UpdateData: Service error: %v\n", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -129,6 +353,10 @@ func (h *Handlers) UpdateData(c *gin.Context) {
 	fmt.Printf("UpdateData: Service call successful\n")
 
 	if !response.Success {
+		if len(response.Forbidden) > 0 {
+			c.JSON(http.StatusForbidden, response)
+			return
+		}
 		// Check if it's a version conflict
 		if response.Error != "" && response.Error[:16] == "Version conflict" {
 			c.JSON(http.StatusConflict, response)
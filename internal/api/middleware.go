@@ -0,0 +1,108 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"roadmap/internal/auth"
+	"roadmap/internal/models"
+)
+
+// requestIDHeader carries the request id between client, server and logs
+const requestIDHeader = "X-Request-ID"
+
+const requestIDKey = "requestId"
+
+// RequestID takes the request id from the X-Request-ID header or generates one,
+// and echoes it in the response
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" || len(requestID) > 255 {
+			requestID = uuid.New().String()
+		}
+		c.Set(requestIDKey, requestID)
+		c.Header(requestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// changeSetMeta collects the request attribution stored with a change set
+func changeSetMeta(c *gin.Context) models.ChangeSetMeta {
+	meta := models.ChangeSetMeta{
+		RequestID: c.GetString(requestIDKey),
+		ClientIP:  c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if value, ok := c.Get(identityKey); ok {
+		meta.APITokenID = value.(*auth.Identity).TokenID
+	}
+	return meta
+}
+
+const identityKey = "identity"
+
+// Authenticate requires every request to carry credentials accepted by the
+// authenticator and stores the caller identity in the context
+func Authenticate(authenticator auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, err := authenticator.Authenticate(c.Request)
+		switch {
+		case err == nil && identity.ReadOnly && c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead:
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "The API token is read-only",
+			})
+		case err == nil:
+			c.Set(identityKey, identity)
+			c.Next()
+		case errors.Is(err, auth.ErrNoCredentials):
+			c.Header("WWW-Authenticate", "Bearer")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Authentication required",
+			})
+		case errors.Is(err, auth.ErrInvalidCredentials):
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid credentials: " + err.Error(),
+			})
+		default:
+			log.Printf("Authentication failed: %v", err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Authentication is temporarily unavailable",
+			})
+		}
+	}
+}
+
+// authenticatedUser replaces the client-supplied userId with the authenticated
+// user. A userId that names someone else is rejected with 403. Without
+// authentication configured the client-supplied value is kept.
+func authenticatedUser(c *gin.Context, userID *string) bool {
+	value, ok := c.Get(identityKey)
+	if !ok {
+		return true
+	}
+	identity := value.(*auth.Identity)
+	if *userID != "" && !strings.EqualFold(*userID, identity.UserID) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "userId does not match the authenticated user",
+		})
+		return false
+	}
+	*userID = identity.UserID
+	return true
+}
+
+// currentUserID returns the authenticated user id, or an empty string when
+// authentication is not configured
+func currentUserID(c *gin.Context) string {
+	if value, ok := c.Get(identityKey); ok {
+		return value.(*auth.Identity).UserID
+	}
+	return ""
+}
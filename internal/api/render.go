@@ -0,0 +1,84 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"roadmap/internal/export"
+)
+
+// RenderGanttSVG returns the task timeline as an SVG image for embedding in
+// pages and emails
+func (h *Handlers) RenderGanttSVG(c *gin.Context) {
+	documentID, gantt, version, ok := h.gantt(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="roadmap-%s-v%d-gantt.svg"`, documentID, version))
+	c.Header("Content-Type", "image/svg+xml; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := export.WriteGanttSVG(c.Writer, gantt); err != nil {
+		log.Printf("Failed to write Gantt SVG of roadmap %s: %v", documentID, err)
+	}
+}
+
+// RenderGanttPDF returns the task timeline as a one-page PDF document
+func (h *Handlers) RenderGanttPDF(c *gin.Context) {
+	if h.pdfFont == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{
+			"error": "PDF rendering is not available: set PDF_FONT to a TrueType font",
+		})
+		return
+	}
+
+	documentID, gantt, version, ok := h.gantt(c)
+	if !ok {
+		return
+	}
+
+	// Rendered in memory so a failure can still be reported
+	var pdf bytes.Buffer
+	if err := export.WriteGanttPDF(&pdf, gantt, h.pdfFont); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to render PDF: " + err.Error(),
+		})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="roadmap-%s-v%d-gantt.pdf"`, documentID, version))
+	c.Data(http.StatusOK, "application/pdf", pdf.Bytes())
+}
+
+// gantt lays out the Gantt chart of the addressed roadmap, grouped by the
+// ?group= parameter. It writes the error response itself and returns false on
+// failure.
+func (h *Handlers) gantt(c *gin.Context) (uuid.UUID, *export.Gantt, int64, bool) {
+	group := export.GanttGroup(c.Query("group"))
+	switch group {
+	case export.GanttNoGroup, export.GanttByTeam, export.GanttByEpic:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid group: must be team or epic",
+		})
+		return uuid.Nil, nil, 0, false
+	}
+
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return uuid.Nil, nil, 0, false
+	}
+
+	data, err := h.service.GetAllData(documentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get data",
+		})
+		return uuid.Nil, nil, 0, false
+	}
+	return documentID, export.NewGantt(data, group), data.Version, true
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"roadmap/internal/auth"
+	"roadmap/internal/models"
+	"roadmap/internal/service"
+)
+
+// GetRoadmaps returns all roadmap documents
+func (h *Handlers) GetRoadmaps(c *gin.Context) {
+	roadmaps, err := h.service.GetRoadmaps(currentUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get roadmaps",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, roadmaps)
+}
+
+// GetRoadmap returns a single roadmap document with its current version
+func (h *Handlers) GetRoadmap(c *gin.Context) {
+	id, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	roadmap, err := h.service.GetRoadmap(id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Roadmap not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get roadmap",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, roadmap)
+}
+
+// CreateRoadmap creates a new empty roadmap document
+func (h *Handlers) CreateRoadmap(c *gin.Context) {
+	// API tokens are bound to an existing roadmap
+	if value, ok := c.Get(identityKey); ok && value.(*auth.Identity).DocumentID != nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "API tokens cannot create roadmaps",
+		})
+		return
+	}
+
+	var req models.CreateRoadmapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Name is required",
+		})
+		return
+	}
+
+	roadmap, err := h.service.CreateRoadmap(currentUserID(c), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create roadmap",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, roadmap)
+}
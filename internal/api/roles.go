@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+	"roadmap/internal/service"
+)
+
+// GetRoles returns the role grants of the roadmap
+func (h *Handlers) GetRoles(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	grants, err := h.service.GetRoleGrants(documentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get roles",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, grants)
+}
+
+// GrantRole sets the role of a user for a team or the whole roadmap
+func (h *Handlers) GrantRole(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	var req models.GrantRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid UserID format: must be a valid UUID",
+		})
+		return
+	}
+	req.UserID = userID.String()
+
+	grant, err := h.service.GrantRole(documentID, currentUserID(c), &req)
+	if err != nil {
+		writeRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, grant)
+}
+
+// RevokeRole removes a role grant
+func (h *Handlers) RevokeRole(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("roleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid role id",
+		})
+		return
+	}
+
+	if err := h.service.RevokeRole(documentID, currentUserID(c), id); err != nil {
+		writeRoleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func writeRoleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidRole):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Internal server error: " + err.Error(),
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+	"roadmap/internal/service"
+)
+
+// GetScenarios returns all scenarios of the roadmap
+func (h *Handlers) GetScenarios(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	scenarios, err := h.service.GetScenarios(documentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get scenarios",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, scenarios)
+}
+
+// GetScenario returns a scenario with its stored overrides
+func (h *Handlers) GetScenario(c *gin.Context) {
+	documentID, scenarioID, ok := h.scenarioID(c)
+	if !ok {
+		return
+	}
+
+	scenario, err := h.service.GetScenario(documentID, scenarioID)
+	if err != nil {
+		writeScenarioError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, scenario)
+}
+
+// CreateScenario forks the current plan into a named scenario
+func (h *Handlers) CreateScenario(c *gin.Context) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateScenarioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Name is required",
+		})
+		return
+	}
+	if !authenticatedUser(c, &req.UserID) {
+		return
+	}
+	if _, err := uuid.Parse(req.UserID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid UserID format: must be a valid UUID",
+		})
+		return
+	}
+
+	scenario, err := h.service.CreateScenario(documentID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to create scenario",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, scenario)
+}
+
+// UpdateScenario records changes in the scenario only
+func (h *Handlers) UpdateScenario(c *gin.Context) {
+	documentID, scenarioID, ok := h.scenarioID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+	if !authenticatedUser(c, &req.UserID) {
+		return
+	}
+	if _, err := uuid.Parse(req.UserID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid UserID format: must be a valid UUID",
+		})
+		return
+	}
+	if !h.hasValidChanges(&req) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "At least one field must be provided for update (not just ID)",
+		})
+		return
+	}
+
+	response, err := h.service.UpdateScenario(documentID, scenarioID, &req)
+	if err != nil {
+		writeScenarioError(c, err)
+		return
+	}
+	writeUpdateResponse(c, response)
+}
+
+// CompareScenario returns per-record differences between main and the scenario
+func (h *Handlers) CompareScenario(c *gin.Context) {
+	documentID, scenarioID, ok := h.scenarioID(c)
+	if !ok {
+		return
+	}
+
+	comparison, err := h.service.CompareScenario(documentID, scenarioID)
+	if err != nil {
+		writeScenarioError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+// MergeScenario applies the scenario to the main plan
+func (h *Handlers) MergeScenario(c *gin.Context) {
+	documentID, scenarioID, ok := h.scenarioID(c)
+	if !ok {
+		return
+	}
+
+	var req models.MergeScenarioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+	if !authenticatedUser(c, &req.UserID) {
+		return
+	}
+	if _, err := uuid.Parse(req.UserID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid UserID format: must be a valid UUID",
+		})
+		return
+	}
+
+	req.Meta = changeSetMeta(c)
+	response, err := h.service.MergeScenario(documentID, scenarioID, &req)
+	if err != nil {
+		writeScenarioError(c, err)
+		return
+	}
+	writeUpdateResponse(c, response)
+}
+
+// DiscardScenario closes the scenario without applying it
+func (h *Handlers) DiscardScenario(c *gin.Context) {
+	documentID, scenarioID, ok := h.scenarioID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DiscardScenario(documentID, currentUserID(c), scenarioID); err != nil {
+		writeScenarioError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// scenarioID resolves the document and the :scenarioId route parameter
+func (h *Handlers) scenarioID(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	scenarioID, err := uuid.Parse(c.Param("scenarioId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid scenario id",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return documentID, scenarioID, true
+}
+
+func writeScenarioError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Scenario not found",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrForbidden) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrScenarioClosed) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": "Internal server error: " + err.Error(),
+	})
+}
+
+// writeUpdateResponse maps an UpdateResponse to 200, 409 on version conflict or 400
+func writeUpdateResponse(c *gin.Context, response *models.UpdateResponse) {
+	if !response.Success {
+		if len(response.Forbidden) > 0 {
+			c.JSON(http.StatusForbidden, response)
+			return
+		}
+		if strings.HasPrefix(response.Error, "Version conflict") {
+			c.JSON(http.StatusConflict, response)
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"roadmap/internal/models"
+	"roadmap/internal/service"
+)
+
+// Simulate runs auto-planning on the current plan with the patch applied
+// in memory and returns the resulting placements without saving anything
+func (h *Handlers) Simulate(c *gin.Context) {
+	var req models.UpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.Simulate(documentID, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPatch) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Internal server error: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+)
+
+// ImportTable previews or commits rows pasted from a spreadsheet. Without
+// commit nothing is written and the response describes what would change.
+func (h *Handlers) ImportTable(c *gin.Context) {
+	var req models.TableImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	if !authenticatedUser(c, &req.UserID) {
+		return
+	}
+
+	// Only a commit is attributed to a user
+	if req.Commit {
+		if req.UserID == "" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "UserID is required",
+			})
+			return
+		}
+		if _, err := uuid.Parse(req.UserID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid UserID format: must be a valid UUID",
+			})
+			return
+		}
+	}
+
+	documentID, ok := h.documentID(c)
+	if !ok {
+		return
+	}
+
+	req.Meta = changeSetMeta(c)
+	response, err := h.service.ImportTable(documentID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Internal server error: " + err.Error(),
+		})
+		return
+	}
+
+	if !response.Success {
+		if len(response.Forbidden) > 0 {
+			c.JSON(http.StatusForbidden, response)
+			return
+		}
+		if strings.HasPrefix(response.Error, "Version conflict") {
+			c.JSON(http.StatusConflict, response)
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"roadmap/internal/auth"
+	"roadmap/internal/models"
+	"roadmap/internal/service"
+)
+
+// GetCurrentUser returns the authenticated identity; clients use its userId
+// instead of generating one
+func (h *Handlers) GetCurrentUser(c *gin.Context) {
+	identity, ok := c.Get(identityKey)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Authentication is not configured",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, identity)
+}
+
+// RegisterCurrentUser creates or updates the display info of the current user.
+// Name and email default to the claims of the identity provider.
+func (h *Handlers) RegisterCurrentUser(c *gin.Context) {
+	var req models.RegisterUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	if !authenticatedUser(c, &req.UserID) {
+		return
+	}
+	if value, ok := c.Get(identityKey); ok {
+		identity := value.(*auth.Identity)
+		if req.DisplayName == "" {
+			req.DisplayName = identity.Name
+		}
+		if req.Email == nil && identity.Email != "" {
+			req.Email = &identity.Email
+		}
+	}
+	if _, err := uuid.Parse(req.UserID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid UserID format: must be a valid UUID",
+		})
+		return
+	}
+
+	user, err := h.service.RegisterUser(&req)
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// GetUsers returns the user directory
+func (h *Handlers) GetUsers(c *gin.Context) {
+	users, err := h.service.GetUsers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get users",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// GetUser returns the display info of a user
+func (h *Handlers) GetUser(c *gin.Context) {
+	user, err := h.service.GetUser(c.Param("userId"))
+	if err != nil {
+		writeUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func writeUserError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User not found",
+		})
+	case errors.Is(err, service.ErrInvalidUser):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Internal server error: " + err.Error(),
+		})
+	}
+}
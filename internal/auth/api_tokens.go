@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"roadmap/internal/models"
+)
+
+// TokenStore looks up API tokens by their secret; it returns nil for unknown,
+// revoked and expired tokens
+type TokenStore interface {
+	AuthenticateAPIToken(secret string) (*models.APIToken, error)
+}
+
+// APITokens authenticates API tokens issued through the API; the caller acts
+// as the service user of the token
+type APITokens struct {
+	store TokenStore
+}
+
+// NewAPITokens creates an authenticator over the token store
+func NewAPITokens(store TokenStore) *APITokens {
+	return &APITokens{store: store}
+}
+
+// Authenticate resolves bearer tokens with the API token prefix
+func (a *APITokens) Authenticate(r *http.Request) (*Identity, error) {
+	secret := bearerToken(r)
+	if !strings.HasPrefix(secret, models.APITokenPrefix) {
+		return nil, ErrNoCredentials
+	}
+
+	token, err := a.lookup(secret)
+	if err != nil {
+		return nil, err
+	}
+	return identityOf(token), nil
+}
+
+// lookup returns the active token with the secret
+func (a *APITokens) lookup(secret string) (*models.APIToken, error) {
+	token, err := a.store.AuthenticateAPIToken(secret)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, fmt.Errorf("API token is unknown, revoked or expired: %w", ErrInvalidCredentials)
+	}
+	return token, nil
+}
+
+// identityOf returns the identity of the service user of the token
+func identityOf(token *models.APIToken) *Identity {
+	return &Identity{
+		UserID:     token.ServiceUserID,
+		Subject:    "api-token:" + token.ID.String(),
+		Name:       token.Name,
+		Method:     "apiToken",
+		TokenID:    &token.ID,
+		DocumentID: &token.DocumentID,
+		ReadOnly:   token.Scope == models.APITokenRead,
+	}
+}
+
+// QueryAPITokens authenticates read API tokens passed as the ?token= query
+// parameter, for clients that cannot send headers, such as calendar apps
+// subscribing to a feed URL. Write tokens are refused: URLs end up in logs
+// and calendar settings.
+type QueryAPITokens struct {
+	tokens *APITokens
+}
+
+// NewQueryAPITokens creates an authenticator of query tokens over the token store
+func NewQueryAPITokens(store TokenStore) *QueryAPITokens {
+	return &QueryAPITokens{tokens: NewAPITokens(store)}
+}
+
+// Authenticate resolves the ?token= query parameter
+func (a *QueryAPITokens) Authenticate(r *http.Request) (*Identity, error) {
+	secret := r.URL.Query().Get("token")
+	if !strings.HasPrefix(secret, models.APITokenPrefix) {
+		return nil, ErrNoCredentials
+	}
+
+	token, err := a.tokens.lookup(secret)
+	if err != nil {
+		return nil, err
+	}
+	if token.Scope != models.APITokenRead {
+		return nil, fmt.Errorf("only read API tokens may be passed in the URL: %w", ErrInvalidCredentials)
+	}
+	return identityOf(token), nil
+}
@@ -0,0 +1,86 @@
+// Package auth derives the identity of the caller from request credentials
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrNoCredentials is returned by an Authenticator when the request carries no
+// credentials it handles, so the next one can try
+var ErrNoCredentials = errors.New("no credentials")
+
+// ErrInvalidCredentials is returned for credentials that were recognized but
+// did not verify
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// subjectNamespace derives stable user ids from subjects that are not UUIDs
+var subjectNamespace = uuid.MustParse("6f1c4f3e-2b8e-4c55-9f0e-1d8a7c3b5e21")
+
+// Identity is the authenticated caller
+type Identity struct {
+	UserID  string `json:"userId"`  // UUID recorded in change_log
+	Subject string `json:"subject"` // Subject as issued by the identity provider or token owner
+	Name    string `json:"name,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Method  string `json:"method"` // "jwt", "token" or "apiToken"
+
+	// Set for API tokens, which are bound to a roadmap
+	TokenID    *uuid.UUID `json:"tokenId,omitempty"`
+	DocumentID *uuid.UUID `json:"documentId,omitempty"`
+	ReadOnly   bool       `json:"readOnly,omitempty"`
+}
+
+// Authenticator verifies the credentials of a request
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Identity, error)
+}
+
+// Chain tries authenticators in order until one accepts the credentials
+type Chain []Authenticator
+
+// Authenticate returns the identity from the first authenticator that accepts
+// the request. When none does, the first rejection is returned, or
+// ErrNoCredentials when no authenticator recognized the credentials.
+func (c Chain) Authenticate(r *http.Request) (*Identity, error) {
+	var rejection error
+	for _, authenticator := range c {
+		identity, err := authenticator.Authenticate(r)
+		switch {
+		case err == nil:
+			return identity, nil
+		case errors.Is(err, ErrNoCredentials):
+		case errors.Is(err, ErrInvalidCredentials):
+			if rejection == nil {
+				rejection = err
+			}
+		default:
+			return nil, err
+		}
+	}
+	if rejection != nil {
+		return nil, rejection
+	}
+	return nil, ErrNoCredentials
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) < 7 || !strings.EqualFold(header[:7], "bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(header[7:])
+}
+
+// userIDFor returns the subject itself when it is a UUID, otherwise a UUID
+// derived from the issuer and subject
+func userIDFor(issuer, subject string) string {
+	if id, err := uuid.Parse(subject); err == nil {
+		return id.String()
+	}
+	return uuid.NewSHA1(subjectNamespace, []byte(issuer+"|"+subject)).String()
+}
@@ -0,0 +1,233 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksRefreshInterval limits how often unknown key ids trigger a JWKS reload
+const jwksRefreshInterval = time.Minute
+
+// OIDC authenticates JWT bearer tokens signed by an OpenID Connect issuer. The
+// signing keys are discovered from the issuer metadata on first use and
+// reloaded when a token references an unknown key.
+type OIDC struct {
+	issuer    string
+	audience  string
+	userClaim string
+	client    *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+// NewOIDC creates an authenticator for tokens of the issuer. An empty audience
+// skips the aud check; userClaim names the claim holding the user id ("sub" by
+// default).
+func NewOIDC(issuer, audience, userClaim string) *OIDC {
+	if userClaim == "" {
+		userClaim = "sub"
+	}
+	return &OIDC{
+		issuer:    strings.TrimSuffix(issuer, "/"),
+		audience:  audience,
+		userClaim: userClaim,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Authenticate verifies the signature, issuer, audience and expiry of the
+// bearer JWT. Opaque tokens are left to the next authenticator.
+func (o *OIDC) Authenticate(r *http.Request) (*Identity, error) {
+	token := bearerToken(r)
+	if token == "" || strings.Count(token, ".") != 2 {
+		return nil, ErrNoCredentials
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(o.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30 * time.Second),
+	}
+	if o.audience != "" {
+		options = append(options, jwt.WithAudience(o.audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, o.key, options...)
+	var fetchErr *keyFetchError
+	if errors.As(err, &fetchErr) {
+		return nil, fetchErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidCredentials)
+	}
+
+	subject, _ := claims[o.userClaim].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("token has no %s claim: %w", o.userClaim, ErrInvalidCredentials)
+	}
+	identity := &Identity{
+		UserID:  userIDFor(o.issuer, subject),
+		Subject: subject,
+		Method:  "jwt",
+	}
+	identity.Name, _ = claims["name"].(string)
+	identity.Email, _ = claims["email"].(string)
+	return identity, nil
+}
+
+// keyFetchError means the issuer keys could not be loaded; the token itself
+// may be valid
+type keyFetchError struct {
+	err error
+}
+
+func (e *keyFetchError) Error() string {
+	return "failed to load issuer keys: " + e.err.Error()
+}
+
+func (e *keyFetchError) Unwrap() error {
+	return e.err
+}
+
+// key returns the verification key referenced by the token header
+func (o *OIDC) key(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	key, ok := o.keys[kid]
+	if !ok && time.Since(o.fetchedAt) >= jwksRefreshInterval {
+		keys, err := o.fetchKeys()
+		if err != nil {
+			return nil, &keyFetchError{err}
+		}
+		o.keys, o.fetchedAt = keys, time.Now()
+		key, ok = keys[kid]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// fetchKeys loads the JWKS advertised by the issuer discovery document
+func (o *OIDC) fetchKeys() (map[string]interface{}, error) {
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := o.getJSON(o.issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != o.issuer {
+		return nil, fmt.Errorf("discovery document issuer %q does not match %q", discovery.Issuer, o.issuer)
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := o.getJSON(discovery.JWKSURI, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]interface{}, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Keys of unsupported types are skipped
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func (o *OIDC) getJSON(url string, dst interface{}) error {
+	resp, err := o.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
+
+// jsonWebKey is an RSA or EC public key in JWK format (RFC 7517)
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k *jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,80 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// utf8BOM makes spreadsheet applications detect the encoding of the file
+const utf8BOM = "\uFEFF"
+
+// WriteCSV writes the grid as CSV: the attribute columns followed by one
+// column per week, labelled by WeekHeader
+func WriteCSV(w io.Writer, grid *Grid) error {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	header := append([]string{}, Columns...)
+	for week := 0; week < grid.Weeks; week++ {
+		header = append(header, grid.WeekHeader(week))
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range grid.Rows {
+		record := make([]string, 0, len(header))
+		for _, value := range row.Values {
+			record = append(record, formatValue(value))
+		}
+		for _, value := range row.Weeks {
+			record = append(record, formatWeek(value))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatValue formats a grid value for a text cell
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return escapeFormula(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return ""
+	}
+}
+
+// escapeFormula prefixes text that spreadsheet applications would evaluate as
+// a formula with an apostrophe, so that it is shown as typed. XLSX cells are
+// typed as strings and need no escaping.
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+// formatWeek formats a week value; empty weeks are left blank as on the plan screen
+func formatWeek(value float64) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,217 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"roadmap/internal/models"
+	"roadmap/internal/planning"
+)
+
+// DiagramFilter selects the tasks of a text Gantt chart; empty fields match
+// every task, others match case-insensitively
+type DiagramFilter struct {
+	Epic string
+	Team string
+}
+
+// diagram is the task timeline as text Gantt formats describe it: sections
+// by epic, tasks with dates derived from their planned weeks and blockers
+type diagram struct {
+	title    string
+	calendar *planning.Calendar
+	sections []diagramSection
+	omitted  int // Matching tasks without planned weeks or cancelled
+}
+
+type diagramSection struct {
+	name  string
+	tasks []*diagramTask
+}
+
+type diagramTask struct {
+	alias      string // T<n> with the 1-based row of the task
+	name       string
+	start, end int // 0-based weeks, moved past the blockers
+	blockers   []*diagramTask
+	after      bool // The blockers, not the plan, set the start
+}
+
+// newDiagram selects the tasks of the roadmap with planned weeks, grouped by
+// epic. Blockers outside the selection are left out.
+func newDiagram(data *models.DataResponse, title string, filter DiagramFilter) *diagram {
+	grid := NewGrid(data)
+	d := &diagram{title: title, calendar: grid.Calendar}
+
+	aliases := make(map[string]string, len(data.Tasks))
+	for i, task := range data.Tasks {
+		aliases[task.ID.String()] = fmt.Sprintf("T%d", i+1)
+	}
+
+	byID := make(map[string]*diagramTask)
+	sections, members := groupTasks(grid.Rows, GanttByEpic)
+	for _, name := range sections {
+		section := diagramSection{name: name}
+		for _, row := range members[name] {
+			task := row.Task
+			if (filter.Epic != "" && !strings.EqualFold(strings.TrimSpace(stringOf(task.Epic)), filter.Epic)) ||
+				(filter.Team != "" && !strings.EqualFold(strings.TrimSpace(task.Team), filter.Team)) {
+				continue
+			}
+			start, end := plannedWeeks(row.Weeks)
+			if start < 0 || (task.Status != nil && *task.Status == models.TaskStatusCancelled) {
+				d.omitted++
+				continue
+			}
+			item := &diagramTask{alias: aliases[task.ID.String()], name: stringOf(task.TaskName), start: start, end: end}
+			byID[task.ID.String()] = item
+			section.tasks = append(section.tasks, item)
+		}
+		if len(section.tasks) > 0 {
+			d.sections = append(d.sections, section)
+		}
+	}
+
+	for _, task := range data.Tasks {
+		item, ok := byID[task.ID.String()]
+		if !ok || task.BlockerIDs == nil {
+			continue
+		}
+		for _, blockerID := range *task.BlockerIDs {
+			if blocker, ok := byID[blockerID]; ok {
+				item.blockers = append(item.blockers, blocker)
+			}
+		}
+	}
+	scheduled := make(map[*diagramTask]bool)
+	for _, section := range d.sections {
+		for _, item := range section.tasks {
+			schedule(item, scheduled)
+		}
+	}
+	return d
+}
+
+// schedule moves the start of a task past the end of its blockers unless the
+// plan starts it later, and the end to no earlier than the start. Blockers
+// are scheduled first; a blocker already in progress closes a cycle and is
+// taken as planned.
+func schedule(task *diagramTask, scheduled map[*diagramTask]bool) {
+	if _, seen := scheduled[task]; seen {
+		return
+	}
+	scheduled[task] = false
+	for _, blocker := range task.blockers {
+		schedule(blocker, scheduled)
+		if blocker.end+1 >= task.start {
+			task.start = blocker.end + 1
+			task.after = true
+		}
+	}
+	if task.end < task.start {
+		task.end = task.start
+	}
+	scheduled[task] = true
+}
+
+// WriteMermaid writes the tasks as a Mermaid gantt chart. Tasks span their
+// planned weeks; a task planned to start before its blockers end starts after
+// them and ends no earlier than that.
+func WriteMermaid(w io.Writer, data *models.DataResponse, title string, filter DiagramFilter) error {
+	d := newDiagram(data, title, filter)
+	names := uniqueNames{}
+
+	var b strings.Builder
+	b.WriteString("gantt\n")
+	fmt.Fprintf(&b, "    title %s\n", mermaidText(d.title))
+	b.WriteString("    dateFormat YYYY-MM-DD\n    axisFormat %d.%m\n")
+	if d.omitted > 0 {
+		fmt.Fprintf(&b, "    %%%% Not shown, without planned weeks or cancelled: %d tasks\n", d.omitted)
+	}
+	for _, section := range d.sections {
+		fmt.Fprintf(&b, "    section %s\n", mermaidText(section.name))
+		for _, task := range section.tasks {
+			// The end date is exclusive
+			end := d.calendar.WeekStart(task.end + 1).Format("2006-01-02")
+			start := d.calendar.WeekStart(task.start).Format("2006-01-02")
+			if task.after {
+				aliases := make([]string, len(task.blockers))
+				for i, blocker := range task.blockers {
+					aliases[i] = blocker.alias
+				}
+				start = "after " + strings.Join(aliases, " ")
+			}
+			fmt.Fprintf(&b, "    %s :%s, %s, %s\n", names.next(mermaidText(task.name)), task.alias, start, end)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WritePlantUML writes the tasks as a PlantUML Gantt diagram. Tasks span
+// their planned weeks; a task planned to start before its blockers end starts
+// at the end of the one ending last and ends no earlier than that.
+func WritePlantUML(w io.Writer, data *models.DataResponse, title string, filter DiagramFilter) error {
+	d := newDiagram(data, title, filter)
+	names := uniqueNames{}
+
+	var b strings.Builder
+	b.WriteString("@startgantt\n")
+	fmt.Fprintf(&b, "title %s\n", plantUMLText(d.title))
+	fmt.Fprintf(&b, "Project starts %s\n", d.calendar.Week0.Format("2006-01-02"))
+	b.WriteString("printscale weekly\n")
+	if d.omitted > 0 {
+		fmt.Fprintf(&b, "' Not shown, without planned weeks or cancelled: %d tasks\n", d.omitted)
+	}
+	for _, section := range d.sections {
+		fmt.Fprintf(&b, "-- %s --\n", plantUMLText(section.name))
+		for _, task := range section.tasks {
+			start := d.calendar.WeekStart(task.start).Format("2006-01-02")
+			if task.after {
+				last := task.blockers[0]
+				for _, blocker := range task.blockers[1:] {
+					if blocker.end > last.end {
+						last = blocker
+					}
+				}
+				start = fmt.Sprintf("at [%s]'s end", last.alias)
+			}
+			fmt.Fprintf(&b, "[%s] as [%s] starts %s and ends %s\n", names.next(plantUMLText(task.name)), task.alias,
+				start, d.calendar.WeekEnd(task.end).Format("2006-01-02"))
+		}
+	}
+	b.WriteString("@endgantt\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mermaidText replaces characters that end a task name or start a comment
+// or an entity with look-alikes
+func mermaidText(value string) string {
+	value = strings.NewReplacer(":", "∶", ";", ",", "#", "＃", "%%", "%").Replace(value)
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// plantUMLText replaces the brackets that delimit task names
+func plantUMLText(value string) string {
+	value = strings.NewReplacer("[", "(", "]", ")").Replace(value)
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// uniqueNames numbers repeated task names: PlantUML identifies tasks by name
+type uniqueNames map[string]int
+
+func (u uniqueNames) next(name string) string {
+	if name == "" {
+		name = "Без названия"
+	}
+	key := strings.ToLower(name)
+	u[key]++
+	if u[key] > 1 {
+		return fmt.Sprintf("%s (%d)", name, u[key])
+	}
+	return name
+}
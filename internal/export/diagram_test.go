@@ -0,0 +1,69 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"roadmap/internal/models"
+)
+
+// diagramData has tasks planned before their blockers end: Build inside the
+// weeks of Design, Deploy inside the weeks Build is moved to
+func diagramData() *models.DataResponse {
+	epic := "API"
+	task := func(name string, weeks []float64, blockers ...*models.Task) models.Task {
+		ids := pq.StringArray{}
+		for _, blocker := range blockers {
+			ids = append(ids, blocker.ID.String())
+		}
+		planned := pq.Float64Array(weeks)
+		return models.Task{ID: uuid.New(), TaskName: &name, Epic: &epic, Weeks: &planned, BlockerIDs: &ids}
+	}
+	design := task("Design", []float64{1, 1, 1})
+	build := task("Build", []float64{0, 1}, &design)
+	docs := task("Docs", []float64{0, 0, 0, 0, 0, 1, 1}, &design)
+	deploy := task("Deploy", []float64{0, 0, 0, 1}, &build)
+	return &models.DataResponse{Tasks: []models.Task{design, build, docs, deploy}}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	var b strings.Builder
+	if err := WriteMermaid(&b, diagramData(), "Roadmap", DiagramFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	want := "gantt\n" +
+		"    title Roadmap\n" +
+		"    dateFormat YYYY-MM-DD\n" +
+		"    axisFormat %d.%m\n" +
+		"    section API\n" +
+		"    Design :T1, 2025-06-02, 2025-06-23\n" +
+		"    Build :T2, after T1, 2025-06-30\n" +
+		"    Docs :T3, 2025-07-07, 2025-07-21\n" +
+		"    Deploy :T4, after T2, 2025-07-07\n"
+	if got := b.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWritePlantUML(t *testing.T) {
+	var b strings.Builder
+	if err := WritePlantUML(&b, diagramData(), "Roadmap", DiagramFilter{}); err != nil {
+		t.Fatal(err)
+	}
+	want := "@startgantt\n" +
+		"title Roadmap\n" +
+		"Project starts 2025-06-02\n" +
+		"printscale weekly\n" +
+		"-- API --\n" +
+		"[Design] as [T1] starts 2025-06-02 and ends 2025-06-22\n" +
+		"[Build] as [T2] starts at [T1]'s end and ends 2025-06-29\n" +
+		"[Docs] as [T3] starts 2025-07-07 and ends 2025-07-20\n" +
+		"[Deploy] as [T4] starts at [T2]'s end and ends 2025-07-06\n" +
+		"@endgantt\n"
+	if got := b.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
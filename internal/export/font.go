@@ -0,0 +1,201 @@
+package export
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fontPaths are where DejaVu Sans is installed by common distributions; it
+// covers Latin and Cyrillic
+var fontPaths = []string{
+	"/usr/share/fonts/dejavu/DejaVuSans.ttf",
+	"/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf",
+	"/usr/share/fonts/TTF/DejaVuSans.ttf",
+}
+
+// Font is a TrueType font embedded into PDF documents
+type Font struct {
+	name       string
+	data       []byte
+	unitsPerEm float64
+	ascent     float64
+	descent    float64
+	bbox       [4]float64
+	advances   []uint16        // Advance width by glyph id
+	glyphs     map[rune]uint16 // Glyph id by character
+}
+
+// LoadFont reads a TrueType font from the path, or from the known locations
+// of DejaVu Sans when the path is empty
+func LoadFont(path string) (*Font, error) {
+	paths := fontPaths
+	if path != "" {
+		paths = []string{path}
+	}
+	for _, candidate := range paths {
+		data, err := os.ReadFile(candidate)
+		if errors.Is(err, os.ErrNotExist) && path == "" {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read font: %w", err)
+		}
+		font, err := parseFont(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load font %s: %w", candidate, err)
+		}
+		font.name = fontName(candidate)
+		return font, nil
+	}
+	return nil, fmt.Errorf("no font found in %s", strings.Join(fontPaths, ", "))
+}
+
+// parseFont reads the metrics and character map of a TrueType font
+func parseFont(data []byte) (font *Font, err error) {
+	defer func() {
+		// Offsets of a malformed font may point outside the data
+		if recover() != nil {
+			font, err = nil, errors.New("malformed font")
+		}
+	}()
+
+	tables := make(map[string][]byte)
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		record := data[12+16*i:]
+		offset, length := binary.BigEndian.Uint32(record[8:]), binary.BigEndian.Uint32(record[12:])
+		tables[string(record[:4])] = data[offset : offset+length]
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "cmap", "glyf"} {
+		if tables[tag] == nil {
+			return nil, fmt.Errorf("not a TrueType font: no %s table", tag)
+		}
+	}
+
+	head, hhea, hmtx := tables["head"], tables["hhea"], tables["hmtx"]
+	font = &Font{
+		data:       data,
+		unitsPerEm: float64(binary.BigEndian.Uint16(head[18:])),
+		ascent:     float64(int16(binary.BigEndian.Uint16(hhea[4:]))),
+		descent:    float64(int16(binary.BigEndian.Uint16(hhea[6:]))),
+		glyphs:     make(map[rune]uint16),
+	}
+	if font.unitsPerEm == 0 {
+		return nil, errors.New("malformed font: no units per em")
+	}
+	for i := range font.bbox {
+		font.bbox[i] = float64(int16(binary.BigEndian.Uint16(head[36+2*i:])))
+	}
+	metrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	font.advances = make([]uint16, metrics)
+	for i := range font.advances {
+		font.advances[i] = binary.BigEndian.Uint16(hmtx[4*i:])
+	}
+
+	if err := font.readCmap(tables["cmap"]); err != nil {
+		return nil, err
+	}
+	return font, nil
+}
+
+// readCmap reads the Unicode character map: format 12 when present, else format 4
+func (f *Font) readCmap(cmap []byte) error {
+	var format4, format12 []byte
+	count := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < count; i++ {
+		record := cmap[4+8*i:]
+		platform, encoding := binary.BigEndian.Uint16(record), binary.BigEndian.Uint16(record[2:])
+		table := cmap[binary.BigEndian.Uint32(record[4:]):]
+		unicode := platform == 0 || (platform == 3 && (encoding == 1 || encoding == 10))
+		switch format := binary.BigEndian.Uint16(table); {
+		case unicode && format == 12:
+			format12 = table
+		case unicode && format == 4 && format4 == nil:
+			format4 = table
+		}
+	}
+
+	switch {
+	case format12 != nil:
+		groups := int(binary.BigEndian.Uint32(format12[12:]))
+		for i := 0; i < groups; i++ {
+			group := format12[16+12*i:]
+			start, end, glyph := binary.BigEndian.Uint32(group), binary.BigEndian.Uint32(group[4:]), binary.BigEndian.Uint32(group[8:])
+			for c := start; c <= end && c <= 0x10FFFF; c++ {
+				f.glyphs[rune(c)] = uint16(glyph + c - start)
+			}
+		}
+	case format4 != nil:
+		segments := int(binary.BigEndian.Uint16(format4[6:])) / 2
+		ends, starts := format4[14:], format4[16+2*segments:]
+		deltas, ranges := starts[2*segments:], starts[4*segments:]
+		for i := 0; i < segments; i++ {
+			start, end := binary.BigEndian.Uint16(starts[2*i:]), binary.BigEndian.Uint16(ends[2*i:])
+			delta, rangeOffset := binary.BigEndian.Uint16(deltas[2*i:]), int(binary.BigEndian.Uint16(ranges[2*i:]))
+			for c := uint32(start); c <= uint32(end) && c != 0xFFFF; c++ {
+				glyph := uint16(c) + delta
+				if rangeOffset != 0 {
+					glyph = binary.BigEndian.Uint16(ranges[2*i+rangeOffset+2*int(c-uint32(start)):])
+					if glyph != 0 {
+						glyph += delta
+					}
+				}
+				f.glyphs[rune(c)] = glyph
+			}
+		}
+	default:
+		return errors.New("no Unicode character map")
+	}
+	return nil
+}
+
+// glyph returns the glyph id of the character; 0 is the missing glyph
+func (f *Font) glyph(r rune) uint16 {
+	return f.glyphs[r]
+}
+
+// advance returns the advance width of the glyph in 1/1000 of the font size
+func (f *Font) advance(glyph uint16) float64 {
+	if len(f.advances) == 0 {
+		return 0
+	}
+	// Glyphs past the metrics share the last advance width
+	width := f.advances[len(f.advances)-1]
+	if int(glyph) < len(f.advances) {
+		width = f.advances[glyph]
+	}
+	return f.scaled(float64(width))
+}
+
+// width returns the width of the text at the font size
+func (f *Font) width(value string, size float64) float64 {
+	total := 0.0
+	for _, r := range value {
+		total += f.advance(f.glyph(r))
+	}
+	return total * size / 1000
+}
+
+// scaled converts font units to 1/1000 of the font size
+func (f *Font) scaled(value float64) float64 {
+	return value * 1000 / f.unitsPerEm
+}
+
+// fontName derives a PDF font name from the file name
+func fontName(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	name = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return -1
+	}, name)
+	if name == "" {
+		return "EmbeddedFont"
+	}
+	return name
+}
@@ -0,0 +1,317 @@
+package export
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode/utf8"
+
+	"roadmap/internal/models"
+)
+
+// GanttGroup is how the Gantt chart groups tasks
+type GanttGroup string
+
+const (
+	GanttNoGroup GanttGroup = ""
+	GanttByTeam  GanttGroup = "team"
+	GanttByEpic  GanttGroup = "epic"
+)
+
+// Layout of the Gantt chart in pixels
+const (
+	ganttMargin      = 12.0
+	ganttLabelWidth  = 260.0
+	ganttWeekWidth   = 24.0
+	ganttSprintRow   = 18.0
+	ganttWeekRow     = 16.0
+	ganttRowHeight   = 22.0
+	ganttBarHeight   = 12.0
+	ganttLegendRow   = 24.0
+	ganttFontSize    = 11.0
+	ganttSmallSize   = 9.0
+	ganttCharWidth   = 0.6 // Average glyph width in em, to truncate labels
+	ganttArrowOffset = 6.0
+)
+
+// Colours of the Gantt chart
+const (
+	ganttHeaderBg   = "#f3f4f6"
+	ganttSprintBg   = "#e5e7eb"
+	ganttGridLine   = "#e2e8f0"
+	ganttSprintLine = "#cbd5e1"
+	ganttText       = "#111827"
+	ganttMutedText  = "#6b7280"
+	ganttArrow      = "#6b7280"
+	ganttCancelled  = "#d1d5db"
+)
+
+// ganttPalette colours functions without a team+fn colour
+var ganttPalette = []string{
+	"#3b82f6", "#10b981", "#f59e0b", "#ef4444", "#8b5cf6",
+	"#ec4899", "#14b8a6", "#f97316", "#6366f1", "#84cc16",
+}
+
+// Gantt is the task timeline of a roadmap laid out for drawing: week and
+// sprint headers, one row per task with a bar over its planned weeks, arrows
+// from blockers and a legend of function colours
+type Gantt struct {
+	Width, Height float64
+
+	grid   *Grid
+	rows   []ganttRow
+	legend []ganttLegendItem
+}
+
+// ganttRow is a group header or a task row
+type ganttRow struct {
+	y          float64 // Top of the row
+	group      string  // Set for group headers
+	task       *models.Task
+	start, end int // 0-based weeks covered by the bar; start is -1 without planned weeks
+	color      string
+}
+
+type ganttLegendItem struct {
+	color, label string
+}
+
+// NewGantt lays out the tasks of the roadmap in table order, optionally
+// grouped by team or epic in the order groups first appear
+func NewGantt(data *models.DataResponse, group GanttGroup) *Gantt {
+	grid := NewGrid(data)
+	gantt := &Gantt{grid: grid}
+
+	groups, members := groupTasks(grid.Rows, group)
+	y := ganttMargin + ganttSprintRow + ganttWeekRow
+	legend := make(map[ganttLegendItem]bool)
+	for _, name := range groups {
+		if group != GanttNoGroup {
+			gantt.rows = append(gantt.rows, ganttRow{y: y, group: name})
+			y += ganttRowHeight
+		}
+		for _, row := range members[name] {
+			taskRow := ganttRow{y: y, task: row.Task, color: ganttColor(row)}
+			taskRow.start, taskRow.end = plannedWeeks(row.Weeks)
+			gantt.rows = append(gantt.rows, taskRow)
+			y += ganttRowHeight
+
+			item := ganttLegendItem{color: taskRow.color, label: stringOf(row.Task.Function)}
+			if item.label == "" {
+				item.label = "—"
+			}
+			if taskRow.start >= 0 && row.Task.Status != nil && *row.Task.Status != models.TaskStatusCancelled && !legend[item] {
+				legend[item] = true
+				gantt.legend = append(gantt.legend, item)
+			}
+		}
+	}
+
+	gantt.Width = 2*ganttMargin + ganttLabelWidth + float64(grid.Weeks)*ganttWeekWidth
+	gantt.Height = y + ganttLegendRow + ganttMargin
+	return gantt
+}
+
+// groupTasks groups the task rows by team or epic, in the order groups first
+// appear. Without grouping all tasks are in one group with an empty name.
+func groupTasks(rows []GridRow, group GanttGroup) ([]string, map[string][]GridRow) {
+	var groups []string
+	members := make(map[string][]GridRow)
+	for _, row := range rows {
+		if row.Task == nil {
+			continue
+		}
+		name := ""
+		switch group {
+		case GanttByTeam:
+			name = strings.TrimSpace(row.Task.Team)
+			if name == "" {
+				name = "Без команды"
+			}
+		case GanttByEpic:
+			name = strings.TrimSpace(stringOf(row.Task.Epic))
+			if name == "" {
+				name = "Без эпика"
+			}
+		}
+		if _, ok := members[name]; !ok {
+			groups = append(groups, name)
+		}
+		members[name] = append(members[name], row)
+	}
+	return groups, members
+}
+
+// plannedWeeks returns the first and last 0-based weeks with load, or -1
+// when no week has any
+func plannedWeeks(weeks []float64) (start, end int) {
+	start, end = -1, -1
+	for w, value := range weeks {
+		if value > 0 {
+			if start < 0 {
+				start = w
+			}
+			end = w
+		}
+	}
+	return start, end
+}
+
+// ganttColor is the bar colour of a task: the team+fn colour, otherwise a
+// palette colour of the function. Cancelled tasks are grey.
+func ganttColor(row GridRow) string {
+	if row.Task.Status != nil && *row.Task.Status == models.TaskStatusCancelled {
+		return ganttCancelled
+	}
+	if color := argb(row.BgColor, ""); color != "" {
+		return "#" + strings.ToLower(color[2:])
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(stringOf(row.Task.Function)))
+	return ganttPalette[hash.Sum32()%uint32(len(ganttPalette))]
+}
+
+// point is a position on the canvas; y grows downwards
+type point struct{ x, y float64 }
+
+type textAnchor int
+
+const (
+	anchorStart textAnchor = iota
+	anchorMiddle
+)
+
+type textStyle struct {
+	size   float64
+	color  string
+	bold   bool
+	anchor textAnchor
+}
+
+// canvas is a drawing surface the Gantt chart is rendered onto. Colours are
+// "#rrggbb", text is positioned by its baseline.
+type canvas interface {
+	rect(x, y, w, h float64, fill string)
+	bar(x, y, w, h float64, fill, title string)
+	line(from, to point, stroke string)
+	arrow(points []point, stroke string)
+	text(at point, value string, style textStyle)
+}
+
+// draw renders the chart onto the canvas
+func (g *Gantt) draw(c canvas) {
+	grid := g.grid
+	left := ganttMargin + ganttLabelWidth
+	top := ganttMargin
+	bottom := g.Height - ganttMargin - ganttLegendRow
+	weekX := func(w int) float64 { return left + float64(w)*ganttWeekWidth }
+
+	c.rect(0, 0, g.Width, g.Height, "#ffffff")
+
+	// Headers: sprints spanning their weeks, then week numbers
+	c.rect(ganttMargin, top, g.Width-2*ganttMargin, ganttSprintRow+ganttWeekRow, ganttHeaderBg)
+	c.text(point{ganttMargin + 4, top + ganttSprintRow + ganttWeekRow - 5}, "Задача", textStyle{size: ganttFontSize, color: ganttText, bold: true})
+	for w := 0; w < grid.Weeks; {
+		code := grid.Calendar.SprintCode(w)
+		span := 1
+		for w+span < grid.Weeks && grid.Calendar.SprintCode(w+span) == code {
+			span++
+		}
+		if code != "" {
+			c.rect(weekX(w), top, float64(span)*ganttWeekWidth, ganttSprintRow, ganttSprintBg)
+			c.text(point{weekX(w) + float64(span)*ganttWeekWidth/2, top + ganttSprintRow - 5}, code,
+				textStyle{size: ganttSmallSize, color: ganttText, bold: true, anchor: anchorMiddle})
+		}
+		c.line(point{weekX(w), top}, point{weekX(w), bottom}, ganttSprintLine)
+		w += span
+	}
+	for w := 0; w < grid.Weeks; w++ {
+		if w > 0 && grid.Calendar.SprintCode(w) == grid.Calendar.SprintCode(w-1) {
+			c.line(point{weekX(w), top + ganttSprintRow}, point{weekX(w), bottom}, ganttGridLine)
+		}
+		c.text(point{weekX(w) + ganttWeekWidth/2, top + ganttSprintRow + ganttWeekRow - 4}, fmt.Sprintf("#%d", w+1),
+			textStyle{size: ganttSmallSize, color: ganttMutedText, anchor: anchorMiddle})
+	}
+	c.line(point{weekX(grid.Weeks), top}, point{weekX(grid.Weeks), bottom}, ganttSprintLine)
+
+	// Rows
+	ends := make(map[string]point)   // Task id -> bar end, for arrows
+	starts := make(map[string]point) // Task id -> bar start
+	for _, row := range g.rows {
+		c.line(point{ganttMargin, row.y + ganttRowHeight}, point{weekX(grid.Weeks), row.y + ganttRowHeight}, ganttGridLine)
+		baseline := row.y + ganttRowHeight/2 + ganttFontSize/2 - 1
+		if row.task == nil {
+			c.rect(ganttMargin, row.y, weekX(grid.Weeks)-ganttMargin, ganttRowHeight, ganttHeaderBg)
+			c.text(point{ganttMargin + 4, baseline}, truncate(row.group, ganttLabelWidth+float64(grid.Weeks)*ganttWeekWidth-8, ganttFontSize),
+				textStyle{size: ganttFontSize, color: ganttText, bold: true})
+			continue
+		}
+
+		name := stringOf(row.task.TaskName)
+		c.text(point{ganttMargin + 4, baseline}, truncate(name, ganttLabelWidth-8, ganttFontSize),
+			textStyle{size: ganttFontSize, color: ganttText})
+		if row.start < 0 {
+			continue
+		}
+		x, w := weekX(row.start), float64(row.end-row.start+1)*ganttWeekWidth
+		barY := row.y + (ganttRowHeight-ganttBarHeight)/2
+		title := fmt.Sprintf("%s: #%d–#%d", name, row.start+1, row.end+1)
+		if fn := stringOf(row.task.Function); fn != "" {
+			title += " · " + fn
+		}
+		if empl := stringOf(row.task.Employee); empl != "" {
+			title += " · " + empl
+		}
+		c.bar(x+1, barY, w-2, ganttBarHeight, row.color, title)
+		id := row.task.ID.String()
+		starts[id] = point{x + 1, row.y + ganttRowHeight/2}
+		ends[id] = point{x + w - 1, row.y + ganttRowHeight/2}
+	}
+
+	// Blocker arrows: from the end of the blocker bar to the start of the task bar
+	for _, row := range g.rows {
+		if row.task == nil || row.task.BlockerIDs == nil {
+			continue
+		}
+		to, ok := starts[row.task.ID.String()]
+		if !ok {
+			continue
+		}
+		for _, blockerID := range *row.task.BlockerIDs {
+			from, ok := ends[blockerID]
+			if !ok {
+				continue
+			}
+			elbow := from.x + ganttArrowOffset
+			if elbow <= to.x-ganttArrowOffset {
+				c.arrow([]point{from, {elbow, from.y}, {elbow, to.y}, to}, ganttArrow)
+				continue
+			}
+			// The task starts before the blocker ends: go round between the rows
+			between := to.y - ganttRowHeight/2
+			if from.y > to.y {
+				between = to.y + ganttRowHeight/2
+			}
+			c.arrow([]point{from, {elbow, from.y}, {elbow, between}, {to.x - ganttArrowOffset, between}, {to.x - ganttArrowOffset, to.y}, to}, ganttArrow)
+		}
+	}
+
+	// Legend of function colours
+	x := ganttMargin
+	y := bottom + ganttLegendRow/2
+	for _, item := range g.legend {
+		c.bar(x, y-5, 10, 10, item.color, item.label)
+		c.text(point{x + 14, y + 4}, item.label, textStyle{size: ganttSmallSize, color: ganttText})
+		x += 14 + float64(utf8.RuneCountInString(item.label))*ganttSmallSize*ganttCharWidth + 12
+	}
+}
+
+// truncate shortens the text to fit the width, ending it with an ellipsis
+func truncate(value string, width, size float64) string {
+	limit := int(width / (size * ganttCharWidth))
+	if utf8.RuneCountInString(value) <= limit {
+		return value
+	}
+	runes := []rune(value)
+	return string(runes[:max(limit-1, 0)]) + "…"
+}
@@ -0,0 +1,109 @@
+package export
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+)
+
+func TestGroupTasks(t *testing.T) {
+	task := func(team, epic string) GridRow {
+		return GridRow{Kind: models.RowKindTask, Task: &models.Task{ID: uuid.New(), Team: team, Epic: &epic}}
+	}
+	rows := []GridRow{
+		task("Core", "Billing"),
+		{Kind: models.RowKindResource, Resource: &models.Resource{ID: uuid.New()}},
+		task(" Mobile ", ""),
+		task("", "Billing"),
+		task("Core", "Search"),
+	}
+
+	tests := []struct {
+		group   GanttGroup
+		want    []string
+		members map[string][]int // group -> indices of rows
+	}{
+		{
+			group:   GanttNoGroup,
+			want:    []string{""},
+			members: map[string][]int{"": {0, 2, 3, 4}},
+		},
+		{
+			group:   GanttByTeam,
+			want:    []string{"Core", "Mobile", "Без команды"},
+			members: map[string][]int{"Core": {0, 4}, "Mobile": {2}, "Без команды": {3}},
+		},
+		{
+			group:   GanttByEpic,
+			want:    []string{"Billing", "Без эпика", "Search"},
+			members: map[string][]int{"Billing": {0, 3}, "Без эпика": {2}, "Search": {4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.group), func(t *testing.T) {
+			groups, members := groupTasks(rows, tt.group)
+			if !reflect.DeepEqual(groups, tt.want) {
+				t.Fatalf("got groups %q, want %q", groups, tt.want)
+			}
+			for name, indices := range tt.members {
+				var ids, want []uuid.UUID
+				for _, row := range members[name] {
+					ids = append(ids, row.Task.ID)
+				}
+				for _, i := range indices {
+					want = append(want, rows[i].Task.ID)
+				}
+				if !reflect.DeepEqual(ids, want) {
+					t.Errorf("group %q: got %v, want %v", name, ids, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPlannedWeeks(t *testing.T) {
+	tests := []struct {
+		name       string
+		weeks      []float64
+		start, end int
+	}{
+		{name: "no weeks", weeks: nil, start: -1, end: -1},
+		{name: "empty plan", weeks: []float64{0, 0, 0}, start: -1, end: -1},
+		{name: "one week", weeks: []float64{0, 0.5, 0}, start: 1, end: 1},
+		{name: "gaps inside the plan", weeks: []float64{0, 1, 0, 0, 2, 0}, start: 1, end: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := plannedWeeks(tt.weeks)
+			if start != tt.start || end != tt.end {
+				t.Errorf("got %d..%d, want %d..%d", start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	// 10 px text fits 5 characters into 33 px
+	tests := []struct {
+		value, want string
+	}{
+		{value: "", want: ""},
+		{value: "API", want: "API"},
+		{value: "Поиск", want: "Поиск"},
+		{value: "Биллинг", want: "Билл…"},
+		{value: "Search v2", want: "Sear…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := truncate(tt.value, 33, 10); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
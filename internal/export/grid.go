@@ -0,0 +1,200 @@
+// Package export renders the plan of a roadmap into file formats for use
+// outside the application
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"roadmap/internal/models"
+	"roadmap/internal/planning"
+)
+
+// Columns are the attribute columns of the plan grid (spec §3.2)
+var Columns = []string{
+	"Type", "Status", "Sprints auto", "Epic", "Task", "Team", "Fn", "Empl",
+	"Plan empl", "Plan weeks", "Blocker names", "Fact", "Start", "End", "Auto",
+}
+
+// Grid is the plan as shown on the plan screen: resources, then tasks, each
+// with its attribute columns and week values
+type Grid struct {
+	Calendar *planning.Calendar
+	Weeks    int
+	Rows     []GridRow
+}
+
+// GridRow is a resource or task row of the grid. Values holds one value per
+// column: a string, a float64, an int, a bool or nil for an empty cell.
+type GridRow struct {
+	Kind     models.RowKind
+	Resource *models.Resource // Set for resource rows
+	Task     *models.Task     // Set for task rows
+	Values   []interface{}
+	Weeks    []float64 // Capacity of resources, load of tasks
+	Load     []float64 // Planned load of resources (spec §6.4); nil for tasks
+
+	// Team+fn colour of task blocks as on the plan screen (spec §10); empty
+	// for the default colour and for resources
+	BgColor, TextColor string
+}
+
+// NewGrid builds the grid of the roadmap data. The timeline covers the
+// sprints and every stored week value.
+func NewGrid(data *models.DataResponse) *Grid {
+	grid := &Grid{Calendar: planning.NewCalendar(data.Sprints)}
+	grid.Weeks = grid.Calendar.Weeks
+	for _, resource := range data.Resources {
+		grid.Weeks = max(grid.Weeks, weeksLen(resource.Weeks))
+	}
+	for _, task := range data.Tasks {
+		grid.Weeks = max(grid.Weeks, weeksLen(task.Weeks))
+	}
+
+	// Load as the plan screen shows it: after auto-planning
+	load := planning.Compute(data).Load
+
+	// Colours are assigned to the team+fn of resource rows; a task takes the
+	// colour of its team and fn
+	colors := make(map[string][2]string)
+	for _, resource := range data.Resources {
+		if resource.FnBgColor != nil && resource.FnTextColor != nil {
+			var teams []string
+			if resource.TeamIDs != nil {
+				teams = append(teams, *resource.TeamIDs...)
+			}
+			sort.Strings(teams)
+			colors[colorKey(strings.Join(teams, "+"), resource.Function)] = [2]string{*resource.FnBgColor, *resource.FnTextColor}
+		}
+	}
+
+	names := make(map[string]string, len(data.Tasks))
+	for _, task := range data.Tasks {
+		names[task.ID.String()] = stringOf(task.TaskName)
+	}
+
+	for i := range data.Resources {
+		resource := &data.Resources[i]
+		teams := ""
+		if resource.TeamIDs != nil {
+			teams = strings.Join(*resource.TeamIDs, ", ")
+		}
+		grid.Rows = append(grid.Rows, GridRow{
+			Kind:     models.RowKindResource,
+			Resource: resource,
+			Values: []interface{}{
+				"Ресурс", nil, nil, nil, nil, teams, stringOf(resource.Function), stringOf(resource.Employee),
+				nil, nil, nil, nil, nil, nil, nil,
+			},
+			Weeks: padded(resource.Weeks, grid.Weeks),
+			Load:  padSlice(load[resource.ID], grid.Weeks),
+		})
+	}
+
+	for i := range data.Tasks {
+		task := &data.Tasks[i]
+		var blockers []string
+		if task.BlockerIDs != nil {
+			for _, id := range *task.BlockerIDs {
+				if name, ok := names[id]; ok {
+					blockers = append(blockers, name)
+				}
+			}
+		}
+		var sprints []string
+		if task.SprintsAuto != nil {
+			sprints = *task.SprintsAuto
+		}
+		status := ""
+		if task.Status != nil {
+			status = string(*task.Status)
+		}
+		color := colors[colorKey(strings.TrimSpace(task.Team), task.Function)]
+		grid.Rows = append(grid.Rows, GridRow{
+			Kind: models.RowKindTask,
+			Task: task,
+			Values: []interface{}{
+				"Задача", status, strings.Join(sprints, ", "), stringOf(task.Epic), stringOf(task.TaskName),
+				task.Team, stringOf(task.Function), stringOf(task.Employee),
+				floatOf(task.PlanEmpl), floatOf(task.PlanWeeks), strings.Join(blockers, ", "), floatOf(task.Fact),
+				intOf(task.StartWeek), intOf(task.EndWeek), task.AutoPlanEnabled == nil || *task.AutoPlanEnabled,
+			},
+			Weeks:     padded(task.Weeks, grid.Weeks),
+			BgColor:   color[0],
+			TextColor: color[1],
+		})
+	}
+	return grid
+}
+
+// WeekHeader labels the 0-based week with its number and sprint code, e.g. "#3 Q3S1"
+func (g *Grid) WeekHeader(idx0 int) string {
+	header := fmt.Sprintf("#%d", idx0+1)
+	if code := g.Calendar.SprintCode(idx0); code != "" {
+		header += " " + code
+	}
+	return header
+}
+
+// WeekBoundary labels the sprint starts and ends within the 0-based week,
+// e.g. "[ 02.06" or "13.06 ]" (spec §3.1)
+func (g *Grid) WeekBoundary(idx0 int) string {
+	starts, ends := g.Calendar.SprintBoundaries(idx0)
+	var labels []string
+	for _, end := range ends {
+		labels = append(labels, end.Format("02.01")+" ]")
+	}
+	for _, start := range starts {
+		labels = append(labels, "[ "+start.Format("02.01"))
+	}
+	return strings.Join(labels, " ")
+}
+
+func colorKey(teams string, fn *string) string {
+	return teams + "|" + stringOf(fn)
+}
+
+func weeksLen(weeks *pq.Float64Array) int {
+	if weeks == nil {
+		return 0
+	}
+	return len(*weeks)
+}
+
+func padded(weeks *pq.Float64Array, n int) []float64 {
+	values := make([]float64, n)
+	if weeks != nil {
+		copy(values, *weeks)
+	}
+	return values
+}
+
+func padSlice(values []float64, n int) []float64 {
+	result := make([]float64, n)
+	copy(result, values)
+	return result
+}
+
+func stringOf(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func floatOf(value *float64) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func intOf(value *int) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
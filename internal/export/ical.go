@@ -0,0 +1,127 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"roadmap/internal/models"
+	"roadmap/internal/planning"
+)
+
+// CalendarFilter selects the tasks of a calendar feed; empty fields match
+// every task, others match case-insensitively. Sprints are always included.
+type CalendarFilter struct {
+	Team     string
+	Employee string
+}
+
+// icsLineLimit is the longest content line in octets; longer lines are folded
+const icsLineLimit = 75
+
+// WriteICS writes an iCalendar feed of the roadmap: all-day events for the
+// start and end of each sprint, and for the start and end of each task on
+// the first day of its startWeek and the last day of its endWeek. Cancelled
+// tasks and tasks without planned weeks are left out.
+func WriteICS(w io.Writer, data *models.DataResponse, name string, filter CalendarFilter) error {
+	calendar := planning.NewCalendar(data.Sprints)
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		writeICSLine(&b, fmt.Sprintf(format, args...))
+	}
+	event := func(uid string, date time.Time, summary, description string) {
+		line("BEGIN:VEVENT")
+		line("UID:%s@roadmap", uid)
+		line("DTSTAMP:%s", stamp)
+		line("DTSTART;VALUE=DATE:%s", date.Format("20060102"))
+		line("DTEND;VALUE=DATE:%s", date.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:%s", icsText(summary))
+		if description != "" {
+			line("DESCRIPTION:%s", icsText(description))
+		}
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//roadmap//plan//RU")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:%s", icsText(name))
+
+	for _, sprint := range data.Sprints {
+		start, okStart := planning.ParseDate(sprint.StartDate)
+		end, okEnd := planning.ParseDate(sprint.EndDate)
+		code := stringOf(sprint.Code)
+		if okStart {
+			event(sprint.ID.String()+"-start", start, "Начало спринта "+code, "")
+		}
+		if okEnd {
+			event(sprint.ID.String()+"-end", end, "Конец спринта "+code, "")
+		}
+	}
+
+	for _, task := range data.Tasks {
+		if (filter.Team != "" && !strings.EqualFold(strings.TrimSpace(task.Team), filter.Team)) ||
+			(filter.Employee != "" && !strings.EqualFold(strings.TrimSpace(stringOf(task.Employee)), filter.Employee)) {
+			continue
+		}
+		if task.StartWeek == nil || task.EndWeek == nil || (task.Status != nil && *task.Status == models.TaskStatusCancelled) {
+			continue
+		}
+
+		var details []string
+		for _, field := range [][2]string{
+			{"Эпик", stringOf(task.Epic)},
+			{"Команда", task.Team},
+			{"Fn", stringOf(task.Function)},
+			{"Empl", stringOf(task.Employee)},
+		} {
+			if field[1] != "" {
+				details = append(details, field[0]+": "+field[1])
+			}
+		}
+		details = append(details, fmt.Sprintf("Недели: #%d–#%d", *task.StartWeek, *task.EndWeek))
+		if task.SprintsAuto != nil && len(*task.SprintsAuto) > 0 {
+			details = append(details, "Спринты: "+strings.Join(*task.SprintsAuto, ", "))
+		}
+		description := strings.Join(details, "\n")
+
+		taskName := stringOf(task.TaskName)
+		event(task.ID.String()+"-start", calendar.WeekStart(*task.StartWeek-1), "Старт: "+taskName, description)
+		event(task.ID.String()+"-end", calendar.WeekEnd(*task.EndWeek-1), "Финиш: "+taskName, description)
+	}
+
+	line("END:VCALENDAR")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeICSLine writes a content line, folding it into continuation lines
+// without splitting characters (RFC 5545 §3.1)
+func writeICSLine(b *strings.Builder, value string) {
+	limit := icsLineLimit
+	for len(value) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(value[cut]) {
+			cut--
+		}
+		b.WriteString(value[:cut])
+		b.WriteString("\r\n ")
+		value = value[cut:]
+		// Continuation lines start with the space
+		limit = icsLineLimit - 1
+	}
+	b.WriteString(value)
+	b.WriteString("\r\n")
+}
+
+// icsText escapes a TEXT value
+func icsText(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
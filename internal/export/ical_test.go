@@ -0,0 +1,79 @@
+package export
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWriteICSLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{
+			name:  "short line",
+			value: "SUMMARY:Релиз",
+			want:  []string{"SUMMARY:Релиз"},
+		},
+		{
+			name:  "exactly the limit",
+			value: strings.Repeat("a", 75),
+			want:  []string{strings.Repeat("a", 75)},
+		},
+		{
+			name:  "one octet over the limit",
+			value: strings.Repeat("a", 76),
+			want:  []string{strings.Repeat("a", 75), " a"},
+		},
+		{
+			name:  "continuation lines hold one octet less",
+			value: strings.Repeat("a", 150),
+			want:  []string{strings.Repeat("a", 75), " " + strings.Repeat("a", 74), " a"},
+		},
+		{
+			name:  "two-octet characters are not split",
+			value: strings.Repeat("Я", 40),
+			want:  []string{strings.Repeat("Я", 37), " " + strings.Repeat("Я", 3)},
+		},
+		{
+			name:  "four-octet characters are not split",
+			value: strings.Repeat("😀", 20),
+			want:  []string{strings.Repeat("😀", 18), " " + strings.Repeat("😀", 2)},
+		},
+		{
+			name:  "mixed widths",
+			value: "SUMMARY:" + strings.Repeat("Задача ✓ ", 12),
+			want: []string{
+				"SUMMARY:Задача ✓ Задача ✓ Задача ✓ Задача ✓",
+				"  Задача ✓ Задача ✓ Задача ✓ Задача ✓ За",
+				" дача ✓ Задача ✓ Задача ✓ Задача ✓ ",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			writeICSLine(&b, tt.value)
+			out := b.String()
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("line %q does not end with CRLF", out)
+			}
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			if !reflect.DeepEqual(lines, tt.want) {
+				t.Errorf("got lines %q, want %q", lines, tt.want)
+			}
+			for _, line := range lines {
+				if len(line) > icsLineLimit || !utf8.ValidString(line) {
+					t.Errorf("line %q has %d octets or invalid UTF-8", line, len(line))
+				}
+			}
+			if unfolded := strings.ReplaceAll(out, "\r\n ", ""); unfolded != tt.value+"\r\n" {
+				t.Errorf("unfolded to %q, want %q", unfolded, tt.value)
+			}
+		})
+	}
+}
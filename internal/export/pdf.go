@@ -0,0 +1,228 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// pdfMaxPage is the largest page side PDF viewers accept, in points
+const pdfMaxPage = 14400.0
+
+// pdfPixel is the size of a pixel in points, as browsers print
+const pdfPixel = 0.75
+
+// WriteGanttPDF writes the Gantt chart as a one-page PDF document with the
+// font embedded
+func WriteGanttPDF(w io.Writer, gantt *Gantt, font *Font) error {
+	// Charts wider or taller than a page allows are scaled down to fit
+	scale := min(pdfPixel, pdfMaxPage/gantt.Width, pdfMaxPage/gantt.Height)
+	c := &pdfCanvas{font: font, height: gantt.Height, used: make(map[uint16]rune)}
+	fmt.Fprintf(&c.b, "%s 0 0 %s 0 0 cm\n", pdfNumber(scale), pdfNumber(scale))
+	gantt.draw(c)
+
+	content, err := deflate(c.b.Bytes())
+	if err != nil {
+		return err
+	}
+	fontFile, err := deflate(font.data)
+	if err != nil {
+		return err
+	}
+
+	doc := &pdfDocument{}
+	doc.add("<< /Type /Catalog /Pages 2 0 R >>")
+	doc.add("<< /Type /Pages /Kids [3 0 R] /Count 1 >>")
+	doc.add(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 5 0 R >> >> /Contents 4 0 R >>",
+		pdfNumber(gantt.Width*scale), pdfNumber(gantt.Height*scale)))
+	doc.addStream("/Filter /FlateDecode", content)
+	doc.add(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [6 0 R] /ToUnicode 9 0 R >>", font.name))
+	doc.add(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor 7 0 R /CIDToGIDMap /Identity /W [%s] >>",
+		font.name, c.widths()))
+	doc.add(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%s %s %s %s] /ItalicAngle 0 /Ascent %s /Descent %s /CapHeight %s /StemV 80 /FontFile2 8 0 R >>",
+		font.name, pdfNumber(font.scaled(font.bbox[0])), pdfNumber(font.scaled(font.bbox[1])), pdfNumber(font.scaled(font.bbox[2])), pdfNumber(font.scaled(font.bbox[3])),
+		pdfNumber(font.scaled(font.ascent)), pdfNumber(font.scaled(font.descent)), pdfNumber(font.scaled(font.ascent))))
+	doc.addStream(fmt.Sprintf("/Filter /FlateDecode /Length1 %d", len(font.data)), fontFile)
+	doc.addStream("", []byte(c.toUnicode()))
+
+	_, err = w.Write(doc.bytes())
+	return err
+}
+
+// pdfCanvas draws into a PDF content stream. PDF coordinates grow upwards,
+// so y is flipped against the height of the chart.
+type pdfCanvas struct {
+	b      bytes.Buffer
+	font   *Font
+	height float64
+	used   map[uint16]rune // Glyphs shown, with the character they stand for
+}
+
+func (c *pdfCanvas) rect(x, y, w, h float64, fill string) {
+	fmt.Fprintf(&c.b, "%s rg %s %s %s %s re f\n", pdfColor(fill), pdfNumber(x), pdfNumber(c.height-y-h), pdfNumber(w), pdfNumber(h))
+}
+
+func (c *pdfCanvas) bar(x, y, w, h float64, fill, _ string) {
+	c.rect(x, y, w, h, fill)
+}
+
+func (c *pdfCanvas) line(from, to point, stroke string) {
+	c.polyline([]point{from, to}, stroke)
+}
+
+func (c *pdfCanvas) arrow(points []point, stroke string) {
+	c.polyline(points, stroke)
+	n := len(points)
+	tip, previous := points[n-1], points[n-2]
+	length := math.Hypot(tip.x-previous.x, tip.y-previous.y)
+	if length == 0 {
+		return
+	}
+	dx, dy := (tip.x-previous.x)/length, (tip.y-previous.y)/length
+	base := point{tip.x - 5*dx, tip.y - 5*dy}
+	fmt.Fprintf(&c.b, "%s rg %s %s m %s %s l %s %s l f\n", pdfColor(stroke),
+		pdfNumber(tip.x), pdfNumber(c.height-tip.y),
+		pdfNumber(base.x-3*dy), pdfNumber(c.height-(base.y+3*dx)),
+		pdfNumber(base.x+3*dy), pdfNumber(c.height-(base.y-3*dx)))
+}
+
+// polyline strokes a line through the points
+func (c *pdfCanvas) polyline(points []point, stroke string) {
+	fmt.Fprintf(&c.b, "%s RG 1 w", pdfColor(stroke))
+	for i, p := range points {
+		operator := "l"
+		if i == 0 {
+			operator = "m"
+		}
+		fmt.Fprintf(&c.b, " %s %s %s", pdfNumber(p.x), pdfNumber(c.height-p.y), operator)
+	}
+	c.b.WriteString(" S\n")
+}
+
+func (c *pdfCanvas) text(at point, value string, style textStyle) {
+	var glyphs strings.Builder
+	for _, r := range value {
+		glyph := c.font.glyph(r)
+		c.used[glyph] = r
+		fmt.Fprintf(&glyphs, "%04X", glyph)
+	}
+	x := at.x
+	if style.anchor == anchorMiddle {
+		x -= c.font.width(value, style.size) / 2
+	}
+	// Bold is emulated by stroking the outlines as well
+	render := "0 Tr"
+	if style.bold {
+		render = fmt.Sprintf("2 Tr 0.3 w %s RG", pdfColor(style.color))
+	}
+	fmt.Fprintf(&c.b, "BT /F1 %s Tf %s rg %s %s %s Td <%s> Tj ET\n",
+		pdfNumber(style.size), pdfColor(style.color), render, pdfNumber(x), pdfNumber(c.height-at.y), glyphs.String())
+}
+
+// widths lists the advance widths of the glyphs shown for the W array
+func (c *pdfCanvas) widths() string {
+	var parts []string
+	for _, glyph := range c.sortedGlyphs() {
+		parts = append(parts, fmt.Sprintf("%d [%s]", glyph, pdfNumber(c.font.advance(glyph))))
+	}
+	return strings.Join(parts, " ")
+}
+
+// toUnicode maps the glyphs shown back to characters so text can be copied
+// and searched
+func (c *pdfCanvas) toUnicode() string {
+	var b strings.Builder
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	b.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	b.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	b.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	glyphs := c.sortedGlyphs()
+	for len(glyphs) > 0 {
+		// A bfchar block holds at most 100 entries
+		block := glyphs[:min(len(glyphs), 100)]
+		glyphs = glyphs[len(block):]
+		fmt.Fprintf(&b, "%d beginbfchar\n", len(block))
+		for _, glyph := range block {
+			fmt.Fprintf(&b, "<%04X> <", glyph)
+			for _, unit := range utf16.Encode([]rune{c.used[glyph]}) {
+				fmt.Fprintf(&b, "%04X", unit)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.String()
+}
+
+func (c *pdfCanvas) sortedGlyphs() []uint16 {
+	glyphs := make([]uint16, 0, len(c.used))
+	for glyph := range c.used {
+		glyphs = append(glyphs, glyph)
+	}
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+	return glyphs
+}
+
+// pdfDocument collects the objects of a PDF file; object n is objects[n-1]
+type pdfDocument struct {
+	objects []string
+}
+
+func (d *pdfDocument) add(object string) {
+	d.objects = append(d.objects, object)
+}
+
+func (d *pdfDocument) addStream(dictionary string, data []byte) {
+	d.add(fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dictionary, len(data), data))
+}
+
+// bytes lays out the objects with the cross-reference table
+func (d *pdfDocument) bytes() []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(d.objects))
+	for i, object := range d.objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(d.objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(d.objects)+1, xref)
+	return b.Bytes()
+}
+
+func deflate(data []byte) ([]byte, error) {
+	var b bytes.Buffer
+	writer := zlib.NewWriter(&b)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// pdfColor converts "#rrggbb" to PDF RGB components
+func pdfColor(color string) string {
+	value, err := strconv.ParseUint(argb(color, "FF000000")[2:], 16, 32)
+	if err != nil {
+		return "0 0 0"
+	}
+	return fmt.Sprintf("%s %s %s", pdfNumber(float64(value>>16&0xFF)/255), pdfNumber(float64(value>>8&0xFF)/255), pdfNumber(float64(value&0xFF)/255))
+}
+
+// pdfNumber formats a number with at most three decimals
+func pdfNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*1000)/1000, 'f', -1, 64)
+}
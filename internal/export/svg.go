@@ -0,0 +1,66 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteGanttSVG writes the Gantt chart as a standalone SVG image
+func WriteGanttSVG(w io.Writer, gantt *Gantt) error {
+	c := &svgCanvas{}
+	fmt.Fprintf(&c.b, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="0 0 %g %g" font-family="DejaVu Sans, Arial, sans-serif">`,
+		gantt.Width, gantt.Height, gantt.Width, gantt.Height)
+	gantt.draw(c)
+	c.b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, c.b.String())
+	return err
+}
+
+// svgCanvas draws SVG elements
+type svgCanvas struct {
+	b       strings.Builder
+	markers map[string]bool // Arrowhead markers defined, by colour
+}
+
+func (c *svgCanvas) rect(x, y, w, h float64, fill string) {
+	fmt.Fprintf(&c.b, `<rect x="%g" y="%g" width="%g" height="%g" fill="%s"/>`, x, y, w, h, fill)
+}
+
+func (c *svgCanvas) bar(x, y, w, h float64, fill, title string) {
+	fmt.Fprintf(&c.b, `<rect x="%g" y="%g" width="%g" height="%g" rx="3" fill="%s"><title>%s</title></rect>`,
+		x, y, w, h, fill, escapeXML(title))
+}
+
+func (c *svgCanvas) line(from, to point, stroke string) {
+	fmt.Fprintf(&c.b, `<line x1="%g" y1="%g" x2="%g" y2="%g" stroke="%s" stroke-width="1"/>`, from.x, from.y, to.x, to.y, stroke)
+}
+
+func (c *svgCanvas) arrow(points []point, stroke string) {
+	id := "arrow-" + strings.TrimPrefix(stroke, "#")
+	if !c.markers[id] {
+		if c.markers == nil {
+			c.markers = make(map[string]bool)
+		}
+		c.markers[id] = true
+		fmt.Fprintf(&c.b, `<defs><marker id="%s" viewBox="0 0 8 8" refX="8" refY="4" markerWidth="6" markerHeight="6" orient="auto"><path d="M0,0 L8,4 L0,8 z" fill="%s"/></marker></defs>`,
+			id, stroke)
+	}
+	coordinates := make([]string, len(points))
+	for i, p := range points {
+		coordinates[i] = fmt.Sprintf("%g,%g", p.x, p.y)
+	}
+	fmt.Fprintf(&c.b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="1" marker-end="url(#%s)"/>`,
+		strings.Join(coordinates, " "), stroke, id)
+}
+
+func (c *svgCanvas) text(at point, value string, style textStyle) {
+	fmt.Fprintf(&c.b, `<text x="%g" y="%g" font-size="%g" fill="%s"`, at.x, at.y, style.size, style.color)
+	if style.bold {
+		c.b.WriteString(` font-weight="bold"`)
+	}
+	if style.anchor == anchorMiddle {
+		c.b.WriteString(` text-anchor="middle"`)
+	}
+	fmt.Fprintf(&c.b, `>%s</text>`, escapeXML(value))
+}
@@ -0,0 +1,349 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Colours of the plan screen (ARGB)
+const (
+	headerBg    = "FFF3F4F6"
+	resourceBg  = "FFF3F4F6"
+	overloadBg  = "FFFEE2E2" // Load above capacity (spec §6.4)
+	underloadBg = "FFDCFCE7" // Load below capacity
+	taskBg      = "FFF5F5F5" // Task blocks without a team+fn colour
+	taskText    = "FF111827"
+	sprintText  = "FF6B7280"
+	dateText    = "FF9CA3AF"
+	borderColor = "FFE2E8F0"
+)
+
+// headerRows are the week number, sprint code and sprint boundary rows
+const headerRows = 3
+
+// columnWidths are the widths of the attribute columns in characters
+var columnWidths = []float64{9, 10, 12, 20, 36, 14, 8, 14, 9, 9, 24, 7, 7, 7, 6}
+
+const weekWidth = 8
+
+var hexColor = regexp.MustCompile(`^#?([0-9a-fA-F]{6}|[0-9a-fA-F]{3})$`)
+
+// WriteXLSX writes the grid as a workbook with one sheet laid out like the
+// plan screen: attribute columns and header rows are frozen, week headers
+// carry sprint codes and boundary dates, resource weeks are highlighted for
+// overload and underload and task weeks take their team+fn colour
+func WriteXLSX(w io.Writer, grid *Grid) error {
+	styles := newStyleSheet()
+	sheet := sheetXML(grid, styles)
+
+	archive := zip.NewWriter(w)
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", styles.xml()},
+		{"xl/worksheets/sheet1.xml", sheet},
+	}
+	for _, part := range parts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// sheetXML renders the worksheet, registering the cell styles it uses
+func sheetXML(grid *Grid, styles *styleSheet) string {
+	header := styles.add(cellStyle{bg: headerBg, bold: true, center: true, wrap: true})
+	sprint := styles.add(cellStyle{bg: headerBg, fg: sprintText, small: true, center: true})
+	boundary := styles.add(cellStyle{bg: headerBg, fg: dateText, small: true, center: true})
+	resource := styles.add(cellStyle{bg: resourceBg})
+	plain := styles.add(cellStyle{})
+	week := styles.add(cellStyle{center: true})
+
+	attributes := len(Columns)
+	lastColumn := cellColumn(attributes + grid.Weeks - 1)
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	fmt.Fprintf(&b, `<dimension ref="A1:%s%d"/>`, lastColumn, headerRows+len(grid.Rows))
+	topLeft := fmt.Sprintf("%s%d", cellColumn(attributes), headerRows+1)
+	fmt.Fprintf(&b, `<sheetViews><sheetView workbookViewId="0"><pane xSplit="%d" ySplit="%d" topLeftCell="%s" activePane="bottomRight" state="frozen"/><selection pane="bottomRight" activeCell="%s" sqref="%s"/></sheetView></sheetViews>`,
+		attributes, headerRows, topLeft, topLeft, topLeft)
+	b.WriteString(`<sheetFormatPr defaultRowHeight="15"/><cols>`)
+	for i, width := range columnWidths {
+		fmt.Fprintf(&b, `<col min="%d" max="%d" width="%g" customWidth="1"/>`, i+1, i+1, width)
+	}
+	if grid.Weeks > 0 {
+		fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, attributes+1, attributes+grid.Weeks, weekWidth)
+	}
+	b.WriteString(`</cols><sheetData>`)
+
+	// Attribute headers span the three header rows
+	for line := 1; line <= headerRows; line++ {
+		fmt.Fprintf(&b, `<row r="%d">`, line)
+		for i, column := range Columns {
+			if line == 1 {
+				writeCell(&b, i, line, header, column)
+			} else {
+				writeCell(&b, i, line, header, nil)
+			}
+		}
+		for w := 0; w < grid.Weeks; w++ {
+			switch line {
+			case 1:
+				writeCell(&b, attributes+w, line, header, fmt.Sprintf("#%d", w+1))
+			case 2:
+				writeCell(&b, attributes+w, line, sprint, grid.Calendar.SprintCode(w))
+			case 3:
+				writeCell(&b, attributes+w, line, boundary, grid.WeekBoundary(w))
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+
+	for i, row := range grid.Rows {
+		line := headerRows + 1 + i
+		fmt.Fprintf(&b, `<row r="%d">`, line)
+		attributeStyle := plain
+		if row.Task == nil {
+			attributeStyle = resource
+		}
+		for column, value := range row.Values {
+			writeCell(&b, column, line, attributeStyle, value)
+		}
+		for w, value := range row.Weeks {
+			style := week
+			if row.Task == nil {
+				switch load := row.Load[w]; {
+				case load > value:
+					style = styles.add(cellStyle{bg: overloadBg, center: true})
+				case value > 0 && load < value:
+					style = styles.add(cellStyle{bg: underloadBg, center: true})
+				}
+			} else if value > 0 {
+				style = styles.add(cellStyle{bg: argb(row.BgColor, taskBg), fg: argb(row.TextColor, taskText), center: true})
+			}
+			var cell interface{}
+			if value != 0 {
+				cell = value
+			}
+			writeCell(&b, attributes+w, line, style, cell)
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData>`)
+
+	fmt.Fprintf(&b, `<mergeCells count="%d">`, attributes)
+	for i := range Columns {
+		fmt.Fprintf(&b, `<mergeCell ref="%s1:%s%d"/>`, cellColumn(i), cellColumn(i), headerRows)
+	}
+	b.WriteString(`</mergeCells></worksheet>`)
+	return b.String()
+}
+
+// writeCell writes a cell of the 0-based column and 1-based line; nil and
+// empty strings give a styled empty cell
+func writeCell(b *strings.Builder, column, line, style int, value interface{}) {
+	ref := fmt.Sprintf("%s%d", cellColumn(column), line)
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			break
+		}
+		fmt.Fprintf(b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, escapeXML(v))
+		return
+	case float64:
+		fmt.Fprintf(b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, strconv.FormatFloat(v, 'f', -1, 64))
+		return
+	case int:
+		fmt.Fprintf(b, `<c r="%s" s="%d"><v>%d</v></c>`, ref, style, v)
+		return
+	case bool:
+		flag := 0
+		if v {
+			flag = 1
+		}
+		fmt.Fprintf(b, `<c r="%s" s="%d" t="b"><v>%d</v></c>`, ref, style, flag)
+		return
+	}
+	fmt.Fprintf(b, `<c r="%s" s="%d"/>`, ref, style)
+}
+
+// cellColumn returns the letters of the 0-based column: A, B, ..., Z, AA, ...
+func cellColumn(idx0 int) string {
+	name := ""
+	for n := idx0 + 1; n > 0; n = (n - 1) / 26 {
+		name = string(rune('A'+(n-1)%26)) + name
+	}
+	return name
+}
+
+// argb converts a "#rrggbb" or "#rgb" colour to ARGB, or returns the fallback
+func argb(color, fallback string) string {
+	match := hexColor.FindStringSubmatch(strings.TrimSpace(color))
+	if match == nil {
+		return fallback
+	}
+	hex := match[1]
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	return "FF" + strings.ToUpper(hex)
+}
+
+func escapeXML(value string) string {
+	var b bytes.Buffer
+	// EscapeText replaces characters XML does not allow, so it cannot fail on a buffer
+	_ = xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
+
+// cellStyle is a combination of formatting used by cells of the sheet
+type cellStyle struct {
+	bg, fg       string // ARGB; empty for none and the default text colour
+	bold, small  bool
+	center, wrap bool
+}
+
+type fontStyle struct {
+	color       string
+	bold, small bool
+}
+
+// styleSheet collects the cell styles of a workbook. Fills 0 and 1 and font
+// 0 are the defaults the format requires; border 1 is the thin grid line.
+type styleSheet struct {
+	fonts  []fontStyle
+	fills  []string
+	styles []cellStyle
+	index  map[cellStyle]int
+}
+
+func newStyleSheet() *styleSheet {
+	return &styleSheet{
+		fonts:  []fontStyle{{}},
+		fills:  []string{"none", "gray125"},
+		styles: []cellStyle{{}},
+		index:  map[cellStyle]int{},
+	}
+}
+
+// add registers the style and returns its index for the s attribute of cells
+func (s *styleSheet) add(style cellStyle) int {
+	if i, ok := s.index[style]; ok {
+		return i
+	}
+	s.styles = append(s.styles, style)
+	s.index[style] = len(s.styles) - 1
+	return len(s.styles) - 1
+}
+
+func (s *styleSheet) font(style cellStyle) int {
+	font := fontStyle{color: style.fg, bold: style.bold, small: style.small}
+	for i, existing := range s.fonts {
+		if existing == font {
+			return i
+		}
+	}
+	s.fonts = append(s.fonts, font)
+	return len(s.fonts) - 1
+}
+
+func (s *styleSheet) fill(bg string) int {
+	if bg == "" {
+		return 0
+	}
+	for i, existing := range s.fills {
+		if existing == bg {
+			return i
+		}
+	}
+	s.fills = append(s.fills, bg)
+	return len(s.fills) - 1
+}
+
+func (s *styleSheet) xml() string {
+	var xfs strings.Builder
+	for i, style := range s.styles {
+		if i == 0 {
+			xfs.WriteString(`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>`)
+			continue
+		}
+		fmt.Fprintf(&xfs, `<xf numFmtId="0" fontId="%d" fillId="%d" borderId="1" xfId="0" applyFont="1" applyFill="1" applyBorder="1" applyAlignment="1"><alignment vertical="center"`,
+			s.font(style), s.fill(style.bg))
+		if style.center {
+			xfs.WriteString(` horizontal="center"`)
+		}
+		if style.wrap {
+			xfs.WriteString(` wrapText="1"`)
+		}
+		xfs.WriteString(`/></xf>`)
+	}
+
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	fmt.Fprintf(&b, `<fonts count="%d">`, len(s.fonts))
+	for _, font := range s.fonts {
+		b.WriteString(`<font>`)
+		if font.bold {
+			b.WriteString(`<b/>`)
+		}
+		size := 11
+		if font.small {
+			size = 9
+		}
+		fmt.Fprintf(&b, `<sz val="%d"/>`, size)
+		if font.color != "" {
+			fmt.Fprintf(&b, `<color rgb="%s"/>`, font.color)
+		}
+		b.WriteString(`<name val="Calibri"/><family val="2"/></font>`)
+	}
+	fmt.Fprintf(&b, `</fonts><fills count="%d">`, len(s.fills))
+	for _, fill := range s.fills {
+		if fill == "none" || fill == "gray125" {
+			fmt.Fprintf(&b, `<fill><patternFill patternType="%s"/></fill>`, fill)
+			continue
+		}
+		fmt.Fprintf(&b, `<fill><patternFill patternType="solid"><fgColor rgb="%s"/><bgColor indexed="64"/></patternFill></fill>`, fill)
+	}
+	b.WriteString(`</fills><borders count="2"><border><left/><right/><top/><bottom/><diagonal/></border>`)
+	fmt.Fprintf(&b, `<border><left style="thin"><color rgb="%[1]s"/></left><right style="thin"><color rgb="%[1]s"/></right><top style="thin"><color rgb="%[1]s"/></top><bottom style="thin"><color rgb="%[1]s"/></bottom><diagonal/></border></borders>`, borderColor)
+	b.WriteString(`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+	fmt.Fprintf(&b, `<cellXfs count="%d">%s</cellXfs>`, len(s.styles), xfs.String())
+	b.WriteString(`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles></styleSheet>`)
+	return b.String()
+}
+
+const xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="План" sheetId="1" r:id="rId1"/></sheets>` +
+	`</workbook>`
+
+const xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
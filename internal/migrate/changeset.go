@@ -0,0 +1,152 @@
+// Package migrate applies the SQL changelog to the database and records the
+// applied changesets with their checksums
+package migrate
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// legacyAuthor is recorded for files written before the liquibase headers
+const legacyAuthor = "legacy"
+
+// Changeset is a unit of the changelog applied in one transaction
+type Changeset struct {
+	ID          string
+	Author      string
+	Filename    string
+	Comment     string
+	SQL         string
+	RunOnChange bool // Reapplied when its checksum changes
+	Checksum    string
+}
+
+// Key identifies the changeset in the tracking table
+func (c *Changeset) Key() string {
+	return c.Filename + "::" + c.ID + "::" + c.Author
+}
+
+// Load reads and parses the *.sql files of the directory in name order
+func Load(fsys fs.FS, dir string) ([]Changeset, error) {
+	names, err := fs.Glob(fsys, path.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	var changesets []Changeset
+	seen := make(map[string]bool)
+	for _, name := range names {
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := Parse(path.Base(name), content)
+		if err != nil {
+			return nil, err
+		}
+		for _, changeset := range parsed {
+			if seen[changeset.ID+"::"+changeset.Author] {
+				return nil, fmt.Errorf("%s: duplicate changeset %s:%s", changeset.Filename, changeset.Author, changeset.ID)
+			}
+			seen[changeset.ID+"::"+changeset.Author] = true
+		}
+		changesets = append(changesets, parsed...)
+	}
+	return changesets, nil
+}
+
+// Parse splits a liquibase formatted SQL file into changesets. Files without
+// the "--liquibase formatted sql" header are one changeset named after the file.
+// Rollback and precondition comments are ignored.
+func Parse(filename string, content []byte) ([]Changeset, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	if len(lines) == 0 || !strings.EqualFold(strings.TrimSpace(lines[0]), "--liquibase formatted sql") {
+		changeset := Changeset{
+			ID:       strings.TrimSuffix(filename, path.Ext(filename)),
+			Author:   legacyAuthor,
+			Filename: filename,
+			SQL:      strings.TrimSpace(strings.Join(lines, "\n")),
+		}
+		changeset.Checksum = checksum(changeset.SQL)
+		return []Changeset{changeset}, nil
+	}
+
+	var changesets []Changeset
+	var current *Changeset
+	var body []string
+	flush := func() {
+		if current != nil {
+			current.SQL = strings.TrimSpace(strings.Join(body, "\n"))
+			current.Checksum = checksum(current.SQL)
+			changesets = append(changesets, *current)
+		}
+		body = nil
+	}
+
+	for i, line := range lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "--changeset "):
+			flush()
+			fields := strings.Fields(strings.TrimPrefix(trimmed, "--changeset "))
+			author, id, ok := strings.Cut(fields[0], ":")
+			if !ok || author == "" || id == "" {
+				return nil, fmt.Errorf("%s:%d: expected --changeset author:id", filename, i+2)
+			}
+			current = &Changeset{ID: id, Author: author, Filename: filename}
+			for _, attribute := range fields[1:] {
+				if name, value, _ := strings.Cut(attribute, ":"); name == "runOnChange" {
+					current.RunOnChange = value == "true"
+				}
+			}
+		case strings.HasPrefix(trimmed, "--comment:"):
+			if current != nil {
+				current.Comment = strings.TrimSpace(strings.TrimPrefix(trimmed, "--comment:"))
+			}
+		case strings.HasPrefix(trimmed, "--rollback"), strings.HasPrefix(trimmed, "--precondition"):
+		default:
+			if current == nil {
+				if trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+					return nil, fmt.Errorf("%s:%d: SQL before the first --changeset", filename, i+2)
+				}
+				continue
+			}
+			body = append(body, line)
+		}
+	}
+	flush()
+
+	if len(changesets) == 0 {
+		return nil, fmt.Errorf("%s: no changesets", filename)
+	}
+	return changesets, nil
+}
+
+// checksum ignores trailing whitespace so that editor settings do not mark a
+// changeset as modified
+func checksum(sql string) string {
+	lines := strings.Split(sql, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t\r")
+	}
+	hash := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,281 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrUntracked is returned when the schema exists but no changeset was
+// recorded, i.e. the database was migrated with psql or Postgres init scripts
+var ErrUntracked = errors.New("database schema exists but applied changesets are not tracked; " +
+	"record the changesets it already has with `migrate sync --through <last applied file>`")
+
+// ErrUnknownFile is returned when sync is limited to a file without changesets
+var ErrUnknownFile = errors.New("no changesets in file")
+
+// ErrModified is returned when an applied changeset was edited afterwards
+var ErrModified = errors.New("applied changeset was modified")
+
+// lockKey is the advisory lock held while migrating, so that instances
+// starting together do not apply changesets twice
+const lockKey = 7461630
+
+const createTrackingTable = `
+	CREATE TABLE IF NOT EXISTS schema_changesets (
+		id VARCHAR(255) NOT NULL,
+		author VARCHAR(255) NOT NULL,
+		filename VARCHAR(255) NOT NULL,
+		checksum CHAR(64) NOT NULL,
+		comment TEXT,
+		applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+		execution_ms BIGINT,
+		PRIMARY KEY (id, author, filename)
+	)`
+
+// State of a changeset in the database
+type State string
+
+const (
+	StatePending  State = "pending"
+	StateApplied  State = "applied"
+	StateModified State = "modified" // Applied with another checksum
+)
+
+// ChangesetStatus represents a changeset and whether it was applied
+type ChangesetStatus struct {
+	Changeset
+	State     State
+	AppliedAt *time.Time
+}
+
+// Migrator applies changesets to a database
+type Migrator struct {
+	db         *sql.DB
+	changesets []Changeset
+}
+
+// New creates a migrator for the changesets in order
+func New(db *sql.DB, changesets []Changeset) *Migrator {
+	return &Migrator{db: db, changesets: changesets}
+}
+
+// Status returns the state of every changeset
+func (m *Migrator) Status(ctx context.Context) ([]ChangesetStatus, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	applied, err := appliedChangesets(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	return m.status(applied), nil
+}
+
+// Up applies pending changesets in order, each in its own transaction, and
+// reapplies modified runOnChange changesets. With dryRun it only writes the
+// SQL that would run. Progress is written to out.
+func (m *Migrator) Up(ctx context.Context, out io.Writer, dryRun bool) (int, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if !dryRun {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+			return 0, fmt.Errorf("failed to lock migrations: %w", err)
+		}
+		defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+
+		if _, err := conn.ExecContext(ctx, createTrackingTable); err != nil {
+			return 0, fmt.Errorf("failed to create schema_changesets: %w", err)
+		}
+	}
+
+	applied, err := appliedChangesets(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if len(applied) == 0 {
+		var schemaExists bool
+		err := conn.QueryRowContext(ctx, "SELECT to_regclass('public.document_versions') IS NOT NULL").Scan(&schemaExists)
+		if err != nil {
+			return 0, err
+		}
+		if schemaExists {
+			return 0, ErrUntracked
+		}
+	}
+
+	statuses := m.status(applied)
+	for _, status := range statuses {
+		if status.State == StateModified && !status.RunOnChange {
+			return 0, fmt.Errorf("%s (%s): %w", status.Key(), status.Filename, ErrModified)
+		}
+	}
+
+	count := 0
+	for _, status := range statuses {
+		if status.State == StateApplied {
+			continue
+		}
+		count++
+
+		if dryRun {
+			fmt.Fprintf(out, "-- Changeset %s (%s)\n%s\n\n", status.Key(), status.State, status.SQL)
+			continue
+		}
+
+		fmt.Fprintf(out, "Applying %s... ", status.Key())
+		elapsed, err := apply(ctx, conn, &status.Changeset)
+		if err != nil {
+			fmt.Fprintln(out, "failed")
+			return count - 1, fmt.Errorf("changeset %s: %w", status.Key(), err)
+		}
+		fmt.Fprintf(out, "done in %s\n", elapsed.Round(time.Millisecond))
+	}
+	return count, nil
+}
+
+// Sync records the pending changesets up to and including the file through
+// as applied without running them, for databases migrated before the
+// changesets were tracked. Later changesets stay pending for Up.
+func (m *Migrator) Sync(ctx context.Context, out io.Writer, through string) (int, error) {
+	last := -1
+	for i, changeset := range m.changesets {
+		if changeset.Filename == through {
+			last = i
+		}
+	}
+	if last < 0 {
+		return 0, fmt.Errorf("%s: %w", through, ErrUnknownFile)
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return 0, fmt.Errorf("failed to lock migrations: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+
+	if _, err := conn.ExecContext(ctx, createTrackingTable); err != nil {
+		return 0, fmt.Errorf("failed to create schema_changesets: %w", err)
+	}
+	applied, err := appliedChangesets(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, status := range m.status(applied)[:last+1] {
+		if status.State != StatePending {
+			continue
+		}
+		if err := record(ctx, conn, &status.Changeset, 0); err != nil {
+			return count, fmt.Errorf("changeset %s: %w", status.Key(), err)
+		}
+		fmt.Fprintf(out, "Marked %s as applied\n", status.Key())
+		count++
+	}
+	return count, nil
+}
+
+func (m *Migrator) status(applied map[string]appliedChangeset) []ChangesetStatus {
+	statuses := make([]ChangesetStatus, 0, len(m.changesets))
+	for _, changeset := range m.changesets {
+		status := ChangesetStatus{Changeset: changeset, State: StatePending}
+		if record, ok := applied[changeset.Key()]; ok {
+			appliedAt := record.appliedAt
+			status.AppliedAt = &appliedAt
+			status.State = StateApplied
+			if record.checksum != changeset.Checksum {
+				status.State = StateModified
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+type appliedChangeset struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+// appliedChangesets returns the tracked changesets by key; none when the
+// tracking table does not exist yet
+func appliedChangesets(ctx context.Context, conn *sql.Conn) (map[string]appliedChangeset, error) {
+	applied := make(map[string]appliedChangeset)
+
+	var exists bool
+	err := conn.QueryRowContext(ctx, "SELECT to_regclass('public.schema_changesets') IS NOT NULL").Scan(&exists)
+	if err != nil || !exists {
+		return applied, err
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT id, author, filename, checksum, applied_at FROM schema_changesets")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var changeset Changeset
+		var record appliedChangeset
+		if err := rows.Scan(&changeset.ID, &changeset.Author, &changeset.Filename, &record.checksum, &record.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[changeset.Key()] = record
+	}
+	return applied, rows.Err()
+}
+
+// apply runs the changeset and records it in one transaction
+func apply(ctx context.Context, conn *sql.Conn, changeset *Changeset) (time.Duration, error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	started := time.Now()
+	if changeset.SQL != "" {
+		if _, err := tx.ExecContext(ctx, changeset.SQL); err != nil {
+			return 0, err
+		}
+	}
+	elapsed := time.Since(started)
+
+	if err := record(ctx, tx, changeset, elapsed); err != nil {
+		return 0, err
+	}
+	return elapsed, tx.Commit()
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// record upserts the changeset into the tracking table
+func record(ctx context.Context, db execer, changeset *Changeset, elapsed time.Duration) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO schema_changesets (id, author, filename, checksum, comment, execution_ms)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+		ON CONFLICT (id, author, filename) DO UPDATE SET
+			checksum = EXCLUDED.checksum,
+			applied_at = NOW(),
+			execution_ms = EXCLUDED.execution_ms
+	`, changeset.ID, changeset.Author, changeset.Filename, changeset.Checksum, changeset.Comment, elapsed.Milliseconds())
+	return err
+}
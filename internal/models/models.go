@@ -116,6 +116,15 @@ type DocumentVersion struct {
 	CreatedAt     time.Time `json:"createdAt" db:"created_at"`
 }
 
+// Roadmap represents an independent roadmap document with its own version counter,
+// teams, sprints, resources and tasks (stored in document_versions)
+type Roadmap struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Version   int64     `json:"version" db:"version_number"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
 // ChangeLog represents a change in the system
 type ChangeLog struct {
 	ID            uuid.UUID   `json:"id" db:"id"`
@@ -124,6 +133,7 @@ type ChangeLog struct {
 	RecordID      uuid.UUID   `json:"recordId" db:"record_id"`
 	Operation     string      `json:"operation" db:"operation"`
 	UserID        *string     `json:"userId,omitempty" db:"user_id"`
+	ChangeSetID   *uuid.UUID  `json:"changeSetId,omitempty" db:"change_set_id"`
 	OldData       interface{} `json:"oldData,omitempty" db:"old_data"`
 	NewData       interface{} `json:"newData,omitempty" db:"new_data"`
 	CreatedAt     time.Time   `json:"createdAt" db:"created_at"`
@@ -147,8 +157,18 @@ type VersionResponse struct {
 
 // DiffResponse represents changes since a specific version
 type DiffResponse struct {
-	Version int64       `json:"version"`
-	Changes []ChangeLog `json:"changes"`
+	Version        int64         `json:"version"`
+	Changes        []ChangeLog   `json:"changes"`
+	HasMore        bool          `json:"hasMore"`
+	NextCursor     *int64        `json:"nextCursor,omitempty"`     // Pass as ?cursor= to get the next page
+	ResyncRequired bool          `json:"resyncRequired,omitempty"` // Requested version was compacted, fetch full data
+	FetchFullData  bool          `json:"fetchFullData,omitempty"`  // Diff would be larger than full data, fetch it instead
+	Users          UserDirectory `json:"users,omitempty"`          // Display info of the authors of the changes
+}
+
+// CreateRoadmapRequest represents a request to create a new roadmap document
+type CreateRoadmapRequest struct {
+	Name string `json:"name"`
 }
 
 // UpdateRequest represents a request to update data
@@ -160,6 +180,16 @@ type UpdateRequest struct {
 	Resources []ResourceUpdate       `json:"resources,omitempty"`
 	Tasks     []TaskUpdate           `json:"tasks,omitempty"`
 	Deleted   map[string][]uuid.UUID `json:"deleted,omitempty"` // table_name -> array of IDs to delete
+	Meta      ChangeSetMeta          `json:"-"`                 // Request attribution filled in by the API layer
+}
+
+// ChangeSetMeta holds request attribution recorded with every change set
+type ChangeSetMeta struct {
+	RequestID  string
+	ClientIP   string
+	UserAgent  string
+	APITokenID *uuid.UUID // Token the request was authenticated with
+	Note       string     // Annotation of changes not made through the API
 }
 
 // ResourceUpdate represents a resource update request
@@ -201,7 +231,550 @@ type TaskUpdate struct {
 
 // UpdateResponse represents the response after updating data
 type UpdateResponse struct {
-	Version int64  `json:"version"`
-	Success bool   `json:"success"`
-	Error   string `json:"error,omitempty"`
+	Version   int64           `json:"version"`
+	Success   bool            `json:"success"`
+	Error     string          `json:"error,omitempty"`
+	Forbidden []ForbiddenItem `json:"forbidden,omitempty"` // Items the user may not change; nothing was applied
+}
+
+// BulkTaskOperation represents an operation applied to a set of tasks at once
+type BulkTaskOperation string
+
+const (
+	BulkOpSetStatus     BulkTaskOperation = "setStatus"
+	BulkOpReassign      BulkTaskOperation = "reassign"
+	BulkOpShiftWeeks    BulkTaskOperation = "shiftWeeks"
+	BulkOpSetAutoPlan   BulkTaskOperation = "setAutoPlan"
+	BulkOpAddBlocker    BulkTaskOperation = "addBlocker"
+	BulkOpRemoveBlocker BulkTaskOperation = "removeBlocker"
+	BulkOpDelete        BulkTaskOperation = "delete"
+)
+
+// BulkTaskFilter selects tasks by attributes; all set fields must match
+type BulkTaskFilter struct {
+	Epic     *string     `json:"epic,omitempty"`
+	TeamID   *uuid.UUID  `json:"teamId,omitempty"`
+	Function *string     `json:"fn,omitempty"`
+	Employee *string     `json:"empl,omitempty"`
+	Status   *TaskStatus `json:"status,omitempty"`
+}
+
+// BulkTaskRequest represents a request to apply one operation to many tasks
+type BulkTaskRequest struct {
+	Version   int64             `json:"version"`
+	UserID    string            `json:"userId"` // Required field
+	Operation BulkTaskOperation `json:"operation"`
+
+	// Target tasks: either an explicit id list or a filter
+	IDs    []uuid.UUID     `json:"ids,omitempty"`
+	Filter *BulkTaskFilter `json:"filter,omitempty"`
+
+	// Operation arguments
+	Status          *TaskStatus `json:"status,omitempty"`          // setStatus
+	TeamID          *uuid.UUID  `json:"teamId,omitempty"`          // reassign
+	Function        *string     `json:"fn,omitempty"`              // reassign
+	Employee        *string     `json:"empl,omitempty"`            // reassign
+	Shift           *int        `json:"shift,omitempty"`           // shiftWeeks, number of weeks (may be negative)
+	AutoPlanEnabled *bool       `json:"autoPlanEnabled,omitempty"` // setAutoPlan
+	BlockerID       *uuid.UUID  `json:"blockerId,omitempty"`       // addBlocker, removeBlocker
+
+	Meta ChangeSetMeta `json:"-"`
+}
+
+// BulkTaskResponse represents the response after a bulk operation
+type BulkTaskResponse struct {
+	Version   int64           `json:"version"`
+	Success   bool            `json:"success"`
+	Error     string          `json:"error,omitempty"`
+	Forbidden []ForbiddenItem `json:"forbidden,omitempty"`
+	Affected  []uuid.UUID     `json:"affected"`
+}
+
+// DuplicateRequest represents a request to duplicate a task or resource row
+type DuplicateRequest struct {
+	Version           int64   `json:"version"`
+	UserID            string  `json:"userId"`                      // Required field
+	CopyWeeks         bool    `json:"copyWeeks"`                   // Copy week values and derived fields as is
+	RepointDependents bool    `json:"repointDependents,omitempty"` // Tasks blocked by the original get blocked by the copy instead
+	Epic              bool    `json:"epic,omitempty"`              // Duplicate every task of the original's epic
+	EpicName          *string `json:"epicName,omitempty"`          // Epic name for the copies, defaults to the original's
+
+	Meta ChangeSetMeta `json:"-"`
+}
+
+// DuplicateResponse represents the response after duplicating rows
+type DuplicateResponse struct {
+	Version   int64                   `json:"version"`
+	Success   bool                    `json:"success"`
+	Error     string                  `json:"error,omitempty"`
+	Forbidden []ForbiddenItem         `json:"forbidden,omitempty"`
+	IDMap     map[uuid.UUID]uuid.UUID `json:"idMap"` // original id -> copy id
+}
+
+// ScenarioStatus represents the lifecycle state of a what-if scenario
+type ScenarioStatus string
+
+const (
+	ScenarioStatusOpen      ScenarioStatus = "open"
+	ScenarioStatusMerged    ScenarioStatus = "merged"
+	ScenarioStatusDiscarded ScenarioStatus = "discarded"
+)
+
+// Scenario represents a named what-if branch: overrides stored on top of a base version
+type Scenario struct {
+	ID          uuid.UUID      `json:"id" db:"id"`
+	Name        string         `json:"name" db:"name"`
+	BaseVersion int64          `json:"baseVersion" db:"base_version"`
+	Version     int64          `json:"version" db:"version"`
+	Status      ScenarioStatus `json:"status" db:"status"`
+	Changes     UpdateRequest  `json:"changes" db:"changes"`
+	CreatedBy   *string        `json:"createdBy,omitempty" db:"created_by"`
+	CreatedAt   time.Time      `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time      `json:"updatedAt" db:"updated_at"`
+}
+
+// CreateScenarioRequest represents a request to fork the current plan into a scenario
+type CreateScenarioRequest struct {
+	Name   string `json:"name"`
+	UserID string `json:"userId"` // Required field
+}
+
+// MergeScenarioRequest represents a request to apply a scenario to the main plan
+type MergeScenarioRequest struct {
+	Version int64  `json:"version"` // Main document version known to the client
+	UserID  string `json:"userId"`  // Required field
+	Force   bool   `json:"force"`   // Overwrite records changed in main since the scenario was forked
+
+	Meta ChangeSetMeta `json:"-"`
+}
+
+// FieldChange represents the old and new value of a single field
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// RecordChange represents the net change of one record with per-field values
+type RecordChange struct {
+	Table     string                 `json:"table"`
+	RecordID  uuid.UUID              `json:"recordId"`
+	Operation string                 `json:"operation"` // INSERT, UPDATE, DELETE
+	Fields    map[string]FieldChange `json:"fields,omitempty"`
+}
+
+// ScenarioComparison represents the difference between the main plan and a scenario
+type ScenarioComparison struct {
+	ScenarioID  uuid.UUID      `json:"scenarioId"`
+	BaseVersion int64          `json:"baseVersion"`
+	MainVersion int64          `json:"mainVersion"`
+	Changes     []RecordChange `json:"changes"`
+}
+
+// TaskPlacement represents where a task lands on the timeline
+type TaskPlacement struct {
+	StartWeek   *int      `json:"startWeek"`
+	EndWeek     *int      `json:"endWeek"`
+	Fact        float64   `json:"fact"`
+	SprintsAuto []string  `json:"sprintsAuto"`
+	Weeks       []float64 `json:"weeks"`
+}
+
+// SimulatedTask represents a task placement before and after the simulated patch.
+// Before is nil for tasks created by the patch, After is nil for deleted tasks.
+type SimulatedTask struct {
+	ID       uuid.UUID      `json:"id"`
+	Task     *string        `json:"task,omitempty"`
+	Epic     *string        `json:"epic,omitempty"`
+	Before   *TaskPlacement `json:"before"`
+	After    *TaskPlacement `json:"after"`
+	EndShift *int           `json:"endShift"` // After.EndWeek - Before.EndWeek when both are planned
+	Changed  bool           `json:"changed"`
+}
+
+// SimulatedResource represents resource capacity and load before and after the
+// simulated patch. FreeDelta is the per-week change of free capacity.
+type SimulatedResource struct {
+	ID             uuid.UUID `json:"id"`
+	Function       *string   `json:"fn,omitempty"`
+	Employee       *string   `json:"empl,omitempty"`
+	CapacityBefore []float64 `json:"capacityBefore"`
+	CapacityAfter  []float64 `json:"capacityAfter"`
+	LoadBefore     []float64 `json:"loadBefore"`
+	LoadAfter      []float64 `json:"loadAfter"`
+	FreeDelta      []float64 `json:"freeDelta"`
+	Changed        bool      `json:"changed"`
+}
+
+// SimulationResponse represents the outcome of a dry-run planning simulation
+type SimulationResponse struct {
+	Version   int64               `json:"version"` // Version the simulation was based on
+	Weeks     int                 `json:"weeks"`
+	Tasks     []SimulatedTask     `json:"tasks"`
+	Resources []SimulatedResource `json:"resources"`
+}
+
+// Baseline represents the plan frozen at a document version under a name
+type Baseline struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Version   int64     `json:"version"`
+	CreatedBy *string   `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CreateBaselineRequest represents a request to freeze the plan as a baseline
+type CreateBaselineRequest struct {
+	Name    string `json:"name"`
+	Version *int64 `json:"version,omitempty"` // Defaults to the current version
+	UserID  string `json:"userId"`
+}
+
+// BaselineTaskStatus classifies a task against the baseline
+type BaselineTaskStatus string
+
+const (
+	BaselineTaskSlipped   BaselineTaskStatus = "slipped"   // ends later than in the baseline
+	BaselineTaskPulledIn  BaselineTaskStatus = "pulledIn"  // ends earlier than in the baseline
+	BaselineTaskChanged   BaselineTaskStatus = "changed"   // same end, other placement or effort
+	BaselineTaskUnchanged BaselineTaskStatus = "unchanged" // same placement and effort
+	BaselineTaskAdded     BaselineTaskStatus = "added"     // scope added after the baseline
+	BaselineTaskRemoved   BaselineTaskStatus = "removed"   // removed after the baseline
+)
+
+// BaselineTaskComparison represents a task in the baseline report.
+// Baseline is nil for added tasks, Current is nil for removed tasks.
+type BaselineTaskComparison struct {
+	ID         uuid.UUID          `json:"id"`
+	Task       *string            `json:"task,omitempty"`
+	Epic       *string            `json:"epic,omitempty"`
+	Team       string             `json:"team"`
+	Status     BaselineTaskStatus `json:"status"`
+	Baseline   *TaskPlacement     `json:"baseline"`
+	Current    *TaskPlacement     `json:"current"`
+	StartShift *int               `json:"startShift"` // Current.StartWeek - Baseline.StartWeek
+	EndShift   *int               `json:"endShift"`   // Current.EndWeek - Baseline.EndWeek
+	FactDelta  float64            `json:"factDelta"`  // Current.Fact - Baseline.Fact
+}
+
+// BaselineReport represents the comparison of the current plan with a baseline
+type BaselineReport struct {
+	Baseline       Baseline                   `json:"baseline"`
+	CurrentVersion int64                      `json:"currentVersion"`
+	Summary        map[BaselineTaskStatus]int `json:"summary"`
+	Tasks          []BaselineTaskComparison   `json:"tasks"`
+}
+
+// VersionDiffResponse represents net field-level changes between two versions
+type VersionDiffResponse struct {
+	From    int64          `json:"from"`
+	To      int64          `json:"to"`
+	Changes []RecordChange `json:"changes"`
+}
+
+// CompactionResult represents the outcome of compacting a document change log
+type CompactionResult struct {
+	DocumentID      uuid.UUID `json:"documentId"`
+	SnapshotVersion int64     `json:"snapshotVersion"` // New start of the retained change log
+	PrunedChanges   int64     `json:"prunedChanges"`
+}
+
+// ReplanResult represents the outcome of re-running auto-planning on the server
+type ReplanResult struct {
+	Weeks        int   `json:"weeks"`        // Length of the plan in weeks
+	ChangedTasks int   `json:"changedTasks"` // Tasks whose saved plan was updated
+	Version      int64 `json:"version"`
+}
+
+// RepairOrderResult represents the number of rows relinked to repair the
+// prev/next order lists
+type RepairOrderResult struct {
+	Resources int   `json:"resources"`
+	Tasks     int   `json:"tasks"`
+	Version   int64 `json:"version"`
+}
+
+// ArchiveFormat identifies roadmap archives written by GET /export
+const ArchiveFormat = "roadmap-archive"
+
+// ArchiveFormatVersion is the archive layout written by GET /export; POST
+// /import reads this and earlier versions
+const ArchiveFormatVersion = 1
+
+// Archive represents a self-contained JSON backup of a roadmap. Resources and
+// tasks are listed in display order, which import restores; their prevId and
+// nextId are kept for reference only.
+type Archive struct {
+	Format        string            `json:"format"`
+	FormatVersion int               `json:"formatVersion"`
+	ExportedAt    time.Time         `json:"exportedAt"`
+	Roadmap       ArchiveRoadmap    `json:"roadmap"`
+	Teams         []Team            `json:"teams"`
+	Sprints       []Sprint          `json:"sprints"`
+	Resources     []ArchiveResource `json:"resources"`
+	Tasks         []ArchiveTask     `json:"tasks"`
+	ChangeLog     []ChangeLog       `json:"changeLog,omitempty"` // Retained change log, with ?includeChangeLog=true
+}
+
+// ArchiveRoadmap describes the exported roadmap document
+type ArchiveRoadmap struct {
+	ID              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	Version         int64     `json:"version"`
+	LogStartVersion int64     `json:"logStartVersion,omitempty"` // Changes up to it were compacted
+}
+
+// ArchiveResource represents a resource row in an archive, with team ids
+// instead of team names
+type ArchiveResource struct {
+	ResourceUpdate
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ArchiveTask represents a task row in an archive
+type ArchiveTask struct {
+	TaskUpdate
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ImportOptions controls how an archive is restored into a roadmap
+type ImportOptions struct {
+	Replace  bool          // Delete the rows of the roadmap first; otherwise it must be empty
+	RemapIDs bool          // Give every row a new id, e.g. to copy a roadmap within one database
+	UserID   string        // Author of the restored rows
+	Meta     ChangeSetMeta // Request attribution filled in by the API layer
+}
+
+// ImportResult represents the outcome of restoring an archive
+type ImportResult struct {
+	DocumentID       uuid.UUID `json:"documentId"`
+	Deleted          int       `json:"deleted"`      // Rows removed by replace
+	RevokedRoles     int       `json:"revokedRoles"` // Team role grants removed with teams missing from the archive
+	Teams            int       `json:"teams"`
+	Sprints          int       `json:"sprints"`
+	Resources        int       `json:"resources"`
+	Tasks            int       `json:"tasks"`
+	RemappedIDs      bool      `json:"remappedIds"`
+	Version          int64     `json:"version"`
+	IgnoredChangeLog int       `json:"ignoredChangeLog"` // Archived change log entries not restored; history starts with the import
+}
+
+// TableImportRequest represents rows pasted from a spreadsheet as CSV or TSV.
+// Without commit only the preview is returned.
+type TableImportRequest struct {
+	Version   int64             `json:"version"`             // Required to commit
+	UserID    string            `json:"userId"`              // Required to commit
+	Content   string            `json:"content"`             // CSV or TSV text
+	Delimiter string            `json:"delimiter,omitempty"` // ",", ";" or "\t"; detected when empty
+	NoHeader  bool              `json:"noHeader,omitempty"`  // The first line is a row; columns are mapped by number
+	Mapping   map[string]string `json:"mapping,omitempty"`   // Column header or 1-based number -> field; known headers are mapped by default
+	Commit    bool              `json:"commit"`
+
+	Meta ChangeSetMeta `json:"-"`
+}
+
+// TableImportAction is what an imported row does to the plan
+type TableImportAction string
+
+const (
+	TableImportCreate    TableImportAction = "create"
+	TableImportUpdate    TableImportAction = "update"
+	TableImportUnchanged TableImportAction = "unchanged"
+	TableImportConflict  TableImportAction = "conflict" // Row cannot be applied; nothing is committed
+)
+
+// TableImportColumn represents how a column of the pasted table is read
+type TableImportColumn struct {
+	Number int    `json:"number"` // 1-based
+	Header string `json:"header,omitempty"`
+	Field  string `json:"field,omitempty"` // Empty for ignored columns
+}
+
+// TableImportRow represents the outcome of one row of the pasted table
+type TableImportRow struct {
+	Line      int               `json:"line"` // Row number in the sheet, counting the header
+	Kind      RowKind           `json:"kind"`
+	Action    TableImportAction `json:"action"`
+	ID        *uuid.UUID        `json:"id,omitempty"`        // Updated row, or the created row once committed
+	Name      string            `json:"name"`                // Task name, or team/fn/empl of a resource
+	Fields    []string          `json:"fields,omitempty"`    // Fields set by a create or changed by an update
+	Conflicts []string          `json:"conflicts,omitempty"` // Why the row cannot be applied
+}
+
+// TableImportResponse represents the preview of a table import and, when
+// committed, its outcome
+type TableImportResponse struct {
+	Version   int64               `json:"version"`
+	Success   bool                `json:"success"`
+	Error     string              `json:"error,omitempty"`
+	Forbidden []ForbiddenItem     `json:"forbidden,omitempty"`
+	Committed bool                `json:"committed"`
+	Columns   []TableImportColumn `json:"columns"`
+	Rows      []TableImportRow    `json:"rows"`
+	Creates   int                 `json:"creates"`
+	Updates   int                 `json:"updates"`
+	Unchanged int                 `json:"unchanged"`
+	Conflicts int                 `json:"conflicts"`
+}
+
+// AttributionGap represents changes without a recorded author grouped into an
+// annotated change set
+type AttributionGap struct {
+	DocumentID  uuid.UUID `json:"documentId"`
+	ChangeSetID uuid.UUID `json:"changeSetId"`
+	FromVersion int64     `json:"fromVersion"`
+	ToVersion   int64     `json:"toVersion"`
+	StartedAt   time.Time `json:"startedAt"`
+	EndedAt     time.Time `json:"endedAt"`
+	Changes     int64     `json:"changes"`
+}
+
+// AuditFilter selects change_log entries for the audit log. Empty values do
+// not filter; From is inclusive and To is exclusive.
+type AuditFilter struct {
+	UserID    string
+	Table     string
+	Operation string
+	RecordID  *uuid.UUID
+	Column    string // Only UPDATEs that changed the column
+	From      *time.Time
+	To        *time.Time
+	Cursor    *int64 // Only entries with a lower version
+	Limit     int
+}
+
+// AuditEntry represents a change_log entry with the metadata of its change set.
+// ChangedFields lists the fields an UPDATE changed compared to the previous
+// logged state of the record; it is omitted when that state is not retained.
+type AuditEntry struct {
+	ChangeLog
+	ChangedFields []string `json:"changedFields,omitempty"`
+	RequestID     *string  `json:"requestId,omitempty"`
+	ClientIP      *string  `json:"clientIp,omitempty"`
+	UserAgent     *string  `json:"userAgent,omitempty"`
+	Note          *string  `json:"note,omitempty"`
+}
+
+// AuditResponse represents a page of the audit log, newest entries first
+type AuditResponse struct {
+	Entries    []AuditEntry  `json:"entries"`
+	HasMore    bool          `json:"hasMore"`
+	NextCursor *int64        `json:"nextCursor,omitempty"` // Pass as cursor to get the next page
+	Users      UserDirectory `json:"users,omitempty"`      // Display info of the authors of the entries
+}
+
+// AuditSummaryResponse represents the number of changes per user per day
+type AuditSummaryResponse struct {
+	Rows  []AuditSummaryRow `json:"rows"`
+	Users UserDirectory     `json:"users,omitempty"`
+}
+
+// AuditSummaryRow represents the number of changes made by a user on a day (UTC)
+type AuditSummaryRow struct {
+	Day        string  `json:"day"` // YYYY-MM-DD
+	UserID     *string `json:"userId"`
+	Changes    int64   `json:"changes"`
+	ChangeSets int64   `json:"changeSets"`
+}
+
+// Role grants permissions on a team, or on the whole roadmap when granted
+// without a team. Each role includes the permissions of the previous ones.
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"   // read only
+	RolePlanner  Role = "planner"  // edit tasks
+	RoleTeamLead Role = "teamLead" // edit resources and capacity
+	RoleAdmin    Role = "admin"    // edit sprints and teams, delete records
+)
+
+// RoleGrant represents a role of a user in a roadmap; TeamID nil means all teams
+type RoleGrant struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    string     `json:"userId" db:"user_id"`
+	Role      Role       `json:"role" db:"role"`
+	TeamID    *uuid.UUID `json:"teamId,omitempty" db:"team_id"`
+	CreatedBy *string    `json:"createdBy,omitempty" db:"created_by"`
+	CreatedAt time.Time  `json:"createdAt" db:"created_at"`
+}
+
+// GrantRoleRequest represents a request to grant a role; it replaces the role
+// the user had for the same team
+type GrantRoleRequest struct {
+	UserID string     `json:"userId"`
+	Role   Role       `json:"role"`
+	TeamID *uuid.UUID `json:"teamId,omitempty"`
+}
+
+// ForbiddenItem represents a change the user lacks the role for
+type ForbiddenItem struct {
+	Table        string    `json:"table"`
+	ID           uuid.UUID `json:"id"`
+	Action       string    `json:"action"` // "update" or "delete"
+	RequiredRole Role      `json:"requiredRole"`
+}
+
+// User represents a registered user with display info
+type User struct {
+	ID          string    `json:"id" db:"id"`
+	DisplayName string    `json:"displayName" db:"display_name"`
+	Email       *string   `json:"email,omitempty" db:"email"`
+	AvatarColor string    `json:"avatarColor" db:"avatar_color"`
+	CreatedAt   time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt   time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// UserDirectory maps user ids to registered users; unregistered ids are absent
+type UserDirectory map[string]User
+
+// RegisterUserRequest represents a request to register or update the current user
+type RegisterUserRequest struct {
+	UserID      string  `json:"userId"`
+	DisplayName string  `json:"displayName"`
+	Email       *string `json:"email,omitempty"`
+	AvatarColor string  `json:"avatarColor,omitempty"` // #RRGGBB, picked from the user id when empty
+}
+
+// APITokenPrefix starts every API token secret
+const APITokenPrefix = "rmt_"
+
+// APITokenScope limits what an API token may do
+type APITokenScope string
+
+const (
+	APITokenRead  APITokenScope = "read"  // GET requests only
+	APITokenWrite APITokenScope = "write" // changes within the token role
+)
+
+// APIToken represents an API token of an automation client. Changes made with
+// it are attributed to ServiceUserID. The secret is only returned on creation.
+type APIToken struct {
+	ID            uuid.UUID     `json:"id" db:"id"`
+	DocumentID    uuid.UUID     `json:"documentId" db:"document_id"`
+	Name          string        `json:"name" db:"name"`
+	Prefix        string        `json:"prefix" db:"token_prefix"`
+	Scope         APITokenScope `json:"scope" db:"scope"`
+	Role          *Role         `json:"role,omitempty" db:"role"`
+	TeamIDs       []uuid.UUID   `json:"teamIds,omitempty" db:"team_ids"`
+	ServiceUserID string        `json:"serviceUserId" db:"service_user_id"`
+	CreatedBy     *string       `json:"createdBy,omitempty" db:"created_by"`
+	CreatedAt     time.Time     `json:"createdAt" db:"created_at"`
+	ExpiresAt     *time.Time    `json:"expiresAt,omitempty" db:"expires_at"`
+	LastUsedAt    *time.Time    `json:"lastUsedAt,omitempty" db:"last_used_at"`
+	RevokedAt     *time.Time    `json:"revokedAt,omitempty" db:"revoked_at"`
+}
+
+// CreateAPITokenRequest represents a request to create an API token. Write
+// tokens act with Role (planner by default) for TeamIDs, or for all teams.
+type CreateAPITokenRequest struct {
+	Name      string        `json:"name"`
+	Scope     APITokenScope `json:"scope"`
+	Role      *Role         `json:"role,omitempty"`
+	TeamIDs   []uuid.UUID   `json:"teamIds,omitempty"`
+	ExpiresAt *time.Time    `json:"expiresAt,omitempty"`
+}
+
+// CreateAPITokenResponse represents a created API token with its secret
+type CreateAPITokenResponse struct {
+	APIToken
+	Token string `json:"token"` // Shown only once
 }
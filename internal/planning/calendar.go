@@ -0,0 +1,144 @@
+package planning
+
+import (
+	"time"
+
+	"roadmap/internal/models"
+)
+
+// defaultWeek0 is the first week used when no sprints are defined (same as the client)
+const defaultWeek0 = "2025-06-02"
+
+// defaultWeeks is the timeline length used when it cannot be derived from sprints
+const defaultWeeks = 16
+
+const week = 7 * 24 * time.Hour
+
+type sprintRange struct {
+	code       string
+	start, end time.Time
+}
+
+// Calendar maps week indices of the plan onto dates and sprints (spec §3.1).
+// Week #1 starts on the start date of the first sprint.
+type Calendar struct {
+	Week0   time.Time
+	Weeks   int
+	sprints []sprintRange
+}
+
+// NewCalendar builds the week scale from sprints ordered by start date
+func NewCalendar(sprints []models.Sprint) *Calendar {
+	calendar := &Calendar{Weeks: defaultWeeks}
+	for _, sprint := range sprints {
+		start, okStart := ParseDate(sprint.StartDate)
+		end, okEnd := ParseDate(sprint.EndDate)
+		if !okStart || !okEnd {
+			continue
+		}
+		code := ""
+		if sprint.Code != nil {
+			code = *sprint.Code
+		}
+		calendar.sprints = append(calendar.sprints, sprintRange{code: code, start: start, end: end})
+	}
+
+	if len(calendar.sprints) == 0 {
+		calendar.Week0, _ = time.Parse("2006-01-02", defaultWeek0)
+		return calendar
+	}
+
+	calendar.Week0 = calendar.sprints[0].start
+	minStart, maxEnd := calendar.sprints[0].start, calendar.sprints[0].end
+	for _, sprint := range calendar.sprints {
+		if sprint.start.Before(minStart) {
+			minStart = sprint.start
+		}
+		if sprint.end.After(maxEnd) {
+			maxEnd = sprint.end
+		}
+	}
+	firstWeek := weeksBetween(calendar.Week0, minStart)
+	lastWeek := weeksBetween(calendar.Week0, maxEnd)
+	calendar.Weeks = lastWeek - firstWeek + 1
+	if calendar.Weeks < 1 {
+		calendar.Weeks = 1
+	}
+	return calendar
+}
+
+// WeekStart returns the first day of the 0-based week
+func (c *Calendar) WeekStart(idx0 int) time.Time {
+	return c.Week0.AddDate(0, 0, 7*idx0)
+}
+
+// WeekEnd returns the last day of the 0-based week
+func (c *Calendar) WeekEnd(idx0 int) time.Time {
+	return c.WeekStart(idx0).AddDate(0, 0, 6)
+}
+
+// SprintCode returns the code of the sprint containing the start of the
+// 0-based week, or an empty string
+func (c *Calendar) SprintCode(idx0 int) string {
+	date := c.WeekStart(idx0)
+	for _, sprint := range c.sprints {
+		if !date.Before(sprint.start) && !date.After(sprint.end) {
+			return sprint.code
+		}
+	}
+	return ""
+}
+
+// SprintBoundaries returns the start and end dates of sprints that fall
+// within the 0-based week, for the "[ DD.MM" and "DD.MM ]" labels of the week
+// header (spec §3.1)
+func (c *Calendar) SprintBoundaries(idx0 int) (starts, ends []time.Time) {
+	from, to := c.WeekStart(idx0), c.WeekEnd(idx0)
+	for _, sprint := range c.sprints {
+		if !sprint.start.Before(from) && !sprint.start.After(to) {
+			starts = append(starts, sprint.start)
+		}
+		if !sprint.end.Before(from) && !sprint.end.After(to) {
+			ends = append(ends, sprint.end)
+		}
+	}
+	return starts, ends
+}
+
+// SprintsBetween returns unique sprint codes of the 1-based weeks
+// [startWeek..endWeek] in ascending week order (spec §3.10)
+func (c *Calendar) SprintsBetween(startWeek, endWeek *int) []string {
+	codes := []string{}
+	if startWeek == nil || endWeek == nil {
+		return codes
+	}
+	seen := make(map[string]bool)
+	for w := *startWeek - 1; w < *endWeek; w++ {
+		if code := c.SprintCode(w); code != "" && !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// ParseDate parses a sprint date stored as YYYY-MM-DD or an RFC 3339 timestamp
+func ParseDate(value *string) (time.Time, bool) {
+	if value == nil || len(*value) < 10 {
+		return time.Time{}, false
+	}
+	date, err := time.Parse("2006-01-02", (*value)[:10])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+func weeksBetween(from, to time.Time) int {
+	diff := to.Sub(from)
+	weeks := int(diff / week)
+	if diff < 0 && diff%week != 0 {
+		weeks--
+	}
+	return weeks
+}
@@ -0,0 +1,318 @@
+// Package planning is the server-side port of the client auto-planning and
+// derived-field computation (spec §3.9, §3.10, §6).
+package planning
+
+import (
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"roadmap/internal/models"
+)
+
+// Result holds the computed plan
+type Result struct {
+	Calendar *Calendar
+	// Tasks in their original order with weeks, fact, startWeek, endWeek and
+	// sprintsAuto recomputed
+	Tasks []models.Task
+	// Capacity and Load are per-resource week arrays sized to the calendar
+	Capacity map[uuid.UUID][]float64
+	Load     map[uuid.UUID][]float64
+}
+
+type resourceState struct {
+	resource *models.Resource
+	load     []float64
+}
+
+// Compute runs auto-planning for tasks with autoPlanEnabled and recomputes
+// derived fields of all tasks. Tasks are processed in topological order of
+// their blockers, ties broken by table order, exactly like the client.
+func Compute(data *models.DataResponse) *Result {
+	calendar := NewCalendar(data.Sprints)
+	totalWeeks := calendar.Weeks
+
+	resources := make([]*resourceState, len(data.Resources))
+	for i := range data.Resources {
+		resources[i] = &resourceState{resource: &data.Resources[i], load: make([]float64, totalWeeks)}
+	}
+
+	index := make(map[string]int, len(data.Tasks))
+	for i, task := range data.Tasks {
+		index[task.ID.String()] = i
+	}
+
+	computed := make([]models.Task, len(data.Tasks))
+	processed := make([]bool, len(data.Tasks))
+	for _, i := range topologicalOrder(data.Tasks, index) {
+		task := data.Tasks[i]
+
+		blocker := 0
+		if task.BlockerIDs != nil {
+			for _, blockerID := range *task.BlockerIDs {
+				j, ok := index[blockerID]
+				if !ok {
+					continue
+				}
+				var endWeek *int
+				if processed[j] {
+					endWeek = computed[j].EndWeek
+				} else {
+					// Only reachable through a dependency cycle
+					endWeek = lastNonZeroWeek(data.Tasks[j].Weeks)
+				}
+				if endWeek != nil && *endWeek > blocker {
+					blocker = *endWeek
+				}
+			}
+		}
+		if task.WeekBlockers != nil {
+			for _, weekNum := range *task.WeekBlockers {
+				if int(weekNum) > blocker {
+					blocker = int(weekNum)
+				}
+			}
+		}
+
+		computed[i] = computeTask(task, blocker, resources, calendar)
+		processed[i] = true
+	}
+
+	result := &Result{
+		Calendar: calendar,
+		Tasks:    computed,
+		Capacity: make(map[uuid.UUID][]float64, len(resources)),
+		Load:     make(map[uuid.UUID][]float64, len(resources)),
+	}
+	for _, rs := range resources {
+		capacities := make([]float64, totalWeeks)
+		for w := range capacities {
+			capacities[w] = capacity(rs.resource, w)
+		}
+		result.Capacity[rs.resource.ID] = capacities
+		result.Load[rs.resource.ID] = rs.load
+	}
+	return result
+}
+
+// computeTask places a single task and allocates its load on matching resources
+func computeTask(task models.Task, blocker int, resources []*resourceState, calendar *Calendar) models.Task {
+	totalWeeks := calendar.Weeks
+	matched := matchResources(task, resources)
+	weeks := make([]float64, totalWeeks)
+
+	if task.AutoPlanEnabled == nil || !*task.AutoPlanEnabled {
+		// Manual plan: keep weeks, only account for the resource load
+		if task.Weeks != nil {
+			copy(weeks, *task.Weeks)
+		}
+		for w, value := range weeks {
+			if value > 0 {
+				allocate(value, matched, w)
+			}
+		}
+	} else {
+		need := math.Max(0, valueOf(task.PlanEmpl))
+		duration := int(math.Ceil(math.Max(0, valueOf(task.PlanWeeks))))
+		start := 0
+		if need > 0 && duration > 0 && len(matched) > 0 {
+			free := make([]float64, totalWeeks)
+			for w := range free {
+				for _, rs := range matched {
+					free[w] += math.Max(0, capacity(rs.resource, w)-rs.load[w])
+				}
+			}
+			for s := max(1, blocker+1); s <= totalWeeks-duration+1; s++ {
+				ok := true
+				for offset := 0; offset < duration; offset++ {
+					if free[s-1+offset] < need {
+						ok = false
+						break
+					}
+				}
+				if ok {
+					start = s
+					break
+				}
+			}
+		}
+		if start > 0 {
+			for offset := 0; offset < duration; offset++ {
+				weeks[start-1+offset] = need
+				allocate(need, matched, start-1+offset)
+			}
+		}
+	}
+
+	result := task
+	weeksArray := pq.Float64Array(weeks)
+	result.Weeks = &weeksArray
+	fact := 0.0
+	for _, value := range weeks {
+		fact += value
+	}
+	result.Fact = &fact
+	result.StartWeek = firstNonZeroWeek(&weeksArray)
+	result.EndWeek = lastNonZeroWeek(&weeksArray)
+	sprints := pq.StringArray(calendar.SprintsBetween(result.StartWeek, result.EndWeek))
+	result.SprintsAuto = &sprints
+	return result
+}
+
+// topologicalOrder returns task indices so that every task comes after its
+// blockers; among ready tasks the table order wins. Tasks left in a cycle are
+// appended in table order.
+func topologicalOrder(tasks []models.Task, index map[string]int) []int {
+	order := make([]int, 0, len(tasks))
+	done := make([]bool, len(tasks))
+	for len(order) < len(tasks) {
+		found := false
+		for i, task := range tasks {
+			if done[i] || !blockersDone(task, index, done) {
+				continue
+			}
+			order = append(order, i)
+			done[i] = true
+			found = true
+			break
+		}
+		if !found {
+			for i := range tasks {
+				if !done[i] {
+					order = append(order, i)
+					done[i] = true
+				}
+			}
+		}
+	}
+	return order
+}
+
+func blockersDone(task models.Task, index map[string]int, done []bool) bool {
+	if task.BlockerIDs == nil {
+		return true
+	}
+	for _, blockerID := range *task.BlockerIDs {
+		// Unknown blockers never become processed in the client either
+		j, ok := index[blockerID]
+		if !ok || !done[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesResource reports whether a task consumes capacity of the resource:
+// same fn, the task team among resource teams, and the same employee when the
+// task is assigned to one (spec §6.1)
+func MatchesResource(task models.Task, resource *models.Resource) bool {
+	if valueOfString(resource.Function) != valueOfString(task.Function) {
+		return false
+	}
+	if task.TeamID == nil {
+		return false
+	}
+	hitTeam := false
+	for _, teamID := range resource.TeamUUIDs {
+		if teamID == task.TeamID.String() {
+			hitTeam = true
+			break
+		}
+	}
+	if !hitTeam {
+		return false
+	}
+	taskEmpl := valueOfString(task.Employee)
+	if taskEmpl != "" && valueOfString(resource.Employee) != taskEmpl {
+		return false
+	}
+	return true
+}
+
+func matchResources(task models.Task, resources []*resourceState) []*resourceState {
+	var matched []*resourceState
+	for _, rs := range resources {
+		if MatchesResource(task, rs.resource) {
+			matched = append(matched, rs)
+		}
+	}
+	return matched
+}
+
+// allocate spreads a week load over matched resources: greedily into free
+// capacity first, the remainder proportionally to free capacity (or evenly)
+func allocate(amount float64, matched []*resourceState, w int) {
+	if amount <= 0 || len(matched) == 0 || w >= len(matched[0].load) {
+		return
+	}
+	freeCaps := make([]float64, len(matched))
+	sumFree := 0.0
+	for j, rs := range matched {
+		freeCaps[j] = math.Max(0, capacity(rs.resource, w)-rs.load[w])
+		sumFree += freeCaps[j]
+	}
+	remain := amount
+	for j := 0; j < len(matched) && remain > 0; j++ {
+		take := math.Min(freeCaps[j], remain)
+		matched[j].load[w] += take
+		remain -= take
+	}
+	if remain > 0 {
+		for j := range matched {
+			if sumFree > 0 {
+				matched[j].load[w] += remain * (freeCaps[j] / sumFree)
+			} else {
+				matched[j].load[w] += remain / float64(len(matched))
+			}
+		}
+	}
+}
+
+func capacity(resource *models.Resource, w int) float64 {
+	if resource.Weeks == nil || w >= len(*resource.Weeks) {
+		return 0
+	}
+	return (*resource.Weeks)[w]
+}
+
+func firstNonZeroWeek(weeks *pq.Float64Array) *int {
+	if weeks == nil {
+		return nil
+	}
+	for i, value := range *weeks {
+		if value > 0 {
+			week := i + 1
+			return &week
+		}
+	}
+	return nil
+}
+
+func lastNonZeroWeek(weeks *pq.Float64Array) *int {
+	if weeks == nil {
+		return nil
+	}
+	for i := len(*weeks) - 1; i >= 0; i-- {
+		if (*weeks)[i] > 0 {
+			week := i + 1
+			return &week
+		}
+	}
+	return nil
+}
+
+func valueOf(value *float64) float64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+func valueOfString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,261 @@
+package planning
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"roadmap/internal/models"
+)
+
+var testTeamID = uuid.MustParse("6f1c1b52-8c1e-4a39-9a57-6c0f2d6d0a01")
+
+// taskSpec describes a task of the team of testResource; blockers are indices
+// of other tasks of the case
+type taskSpec struct {
+	fn, empl     string
+	need, dur    float64
+	manual       []float64
+	blockers     []int
+	weekBlockers []int64
+}
+
+type plannedTask struct {
+	weeks      []float64
+	start, end int
+	sprints    []string
+}
+
+func testResource(fn, empl string, capacity ...float64) models.Resource {
+	weeks := pq.Float64Array(capacity)
+	return models.Resource{
+		ID:        uuid.New(),
+		TeamUUIDs: pq.StringArray{testTeamID.String()},
+		Function:  &fn,
+		Employee:  &empl,
+		Weeks:     &weeks,
+	}
+}
+
+func testTasks(specs []taskSpec) []models.Task {
+	tasks := make([]models.Task, len(specs))
+	for i := range specs {
+		tasks[i].ID = uuid.New()
+	}
+	for i, spec := range specs {
+		task := &tasks[i]
+		fn, empl, need, dur := spec.fn, spec.empl, spec.need, spec.dur
+		task.TeamID = &testTeamID
+		task.Function = &fn
+		task.Employee = &empl
+		task.PlanEmpl = &need
+		task.PlanWeeks = &dur
+		auto := spec.manual == nil
+		task.AutoPlanEnabled = &auto
+		if spec.manual != nil {
+			weeks := pq.Float64Array(spec.manual)
+			task.Weeks = &weeks
+		}
+		blockers := pq.StringArray{}
+		for _, j := range spec.blockers {
+			blockers = append(blockers, tasks[j].ID.String())
+		}
+		task.BlockerIDs = &blockers
+		weekBlockers := pq.Int64Array(spec.weekBlockers)
+		task.WeekBlockers = &weekBlockers
+	}
+	return tasks
+}
+
+func testSprint(code, start, end string) models.Sprint {
+	return models.Sprint{ID: uuid.New(), Code: &code, StartDate: &start, EndDate: &end}
+}
+
+// Expected plans are the ones the client computes for the same rows
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name      string
+		sprints   []models.Sprint
+		resources []models.Resource
+		tasks     []taskSpec
+		want      []plannedTask
+	}{
+		{
+			name:      "first weeks with enough free capacity",
+			resources: []models.Resource{testResource("BE", "Ann", 0, 1, 1, 1, 1)},
+			tasks:     []taskSpec{{fn: "BE", need: 1, dur: 2}},
+			want:      []plannedTask{{weeks: []float64{0, 1, 1}, start: 2, end: 3}},
+		},
+		{
+			name:      "fractional duration is rounded up",
+			resources: []models.Resource{testResource("BE", "Ann", 1, 1, 1)},
+			tasks:     []taskSpec{{fn: "BE", need: 0.5, dur: 1.5}},
+			want:      []plannedTask{{weeks: []float64{0.5, 0.5}, start: 1, end: 2}},
+		},
+		{
+			name:      "blocked task starts after the blocker ends",
+			resources: []models.Resource{testResource("BE", "Ann", 2, 2, 2, 2)},
+			tasks: []taskSpec{
+				{fn: "BE", need: 1, dur: 2},
+				{fn: "BE", need: 1, dur: 1, blockers: []int{0}},
+			},
+			want: []plannedTask{
+				{weeks: []float64{1, 1}, start: 1, end: 2},
+				{weeks: []float64{0, 0, 1}, start: 3, end: 3},
+			},
+		},
+		{
+			name:      "blocker below in the table is planned first",
+			resources: []models.Resource{testResource("BE", "Ann", 2, 2, 2, 2)},
+			tasks: []taskSpec{
+				{fn: "BE", need: 1, dur: 1, blockers: []int{1}},
+				{fn: "BE", need: 2, dur: 2},
+			},
+			want: []plannedTask{
+				{weeks: []float64{0, 0, 1}, start: 3, end: 3},
+				{weeks: []float64{2, 2}, start: 1, end: 2},
+			},
+		},
+		{
+			name:      "week blocker",
+			resources: []models.Resource{testResource("BE", "Ann", 1, 1, 1, 1, 1)},
+			tasks:     []taskSpec{{fn: "BE", need: 1, dur: 1, weekBlockers: []int64{1, 3}}},
+			want:      []plannedTask{{weeks: []float64{0, 0, 0, 1}, start: 4, end: 4}},
+		},
+		{
+			name:      "manual plan is kept and takes capacity",
+			resources: []models.Resource{testResource("BE", "Ann", 1, 1, 1, 1)},
+			tasks: []taskSpec{
+				{fn: "BE", manual: []float64{1, 1}},
+				{fn: "BE", need: 1, dur: 1},
+			},
+			want: []plannedTask{
+				{weeks: []float64{1, 1}, start: 1, end: 2},
+				{weeks: []float64{0, 0, 1}, start: 3, end: 3},
+			},
+		},
+		{
+			name:      "resource of another function",
+			resources: []models.Resource{testResource("BE", "Ann", 1, 1, 1)},
+			tasks:     []taskSpec{{fn: "QA", need: 1, dur: 1}},
+			want:      []plannedTask{{}},
+		},
+		{
+			name:      "resource of another employee",
+			resources: []models.Resource{testResource("BE", "Ann", 1, 1, 1)},
+			tasks:     []taskSpec{{fn: "BE", empl: "Bob", need: 1, dur: 1}},
+			want:      []plannedTask{{}},
+		},
+		{
+			name:      "capacity below the need",
+			resources: []models.Resource{testResource("BE", "Ann", 1, 1, 1)},
+			tasks:     []taskSpec{{fn: "BE", need: 2, dur: 1}},
+			want:      []plannedTask{{}},
+		},
+		{
+			name: "sprints of the planned weeks",
+			sprints: []models.Sprint{
+				testSprint("Q1S1", "2025-06-02", "2025-06-15"),
+				testSprint("Q1S2", "2025-06-16", "2025-06-29"),
+			},
+			resources: []models.Resource{testResource("BE", "Ann", 1, 1, 1, 1)},
+			tasks:     []taskSpec{{fn: "BE", need: 1, dur: 2, weekBlockers: []int64{1}}},
+			want:      []plannedTask{{weeks: []float64{0, 1, 1}, start: 2, end: 3, sprints: []string{"Q1S1", "Q1S2"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &models.DataResponse{Sprints: tt.sprints, Resources: tt.resources, Tasks: testTasks(tt.tasks)}
+			result := Compute(data)
+
+			for i, want := range tt.want {
+				task := result.Tasks[i]
+				if task.ID != data.Tasks[i].ID {
+					t.Fatalf("task %d: got id %s, want %s", i, task.ID, data.Tasks[i].ID)
+				}
+
+				weeks := make([]float64, result.Calendar.Weeks)
+				copy(weeks, want.weeks)
+				if !reflect.DeepEqual([]float64(*task.Weeks), weeks) {
+					t.Errorf("task %d: weeks %v, want %v", i, *task.Weeks, weeks)
+				}
+				fact := 0.0
+				for _, value := range want.weeks {
+					fact += value
+				}
+				if *task.Fact != fact {
+					t.Errorf("task %d: fact %v, want %v", i, *task.Fact, fact)
+				}
+				if got := weekNumber(task.StartWeek); got != want.start {
+					t.Errorf("task %d: start week %d, want %d", i, got, want.start)
+				}
+				if got := weekNumber(task.EndWeek); got != want.end {
+					t.Errorf("task %d: end week %d, want %d", i, got, want.end)
+				}
+				sprints := want.sprints
+				if sprints == nil {
+					sprints = []string{}
+				}
+				if !reflect.DeepEqual([]string(*task.SprintsAuto), sprints) {
+					t.Errorf("task %d: sprints %v, want %v", i, *task.SprintsAuto, sprints)
+				}
+			}
+		})
+	}
+}
+
+func TestComputeLoad(t *testing.T) {
+	tests := []struct {
+		name       string
+		capacities []float64
+		tasks      []taskSpec
+		want       [][]float64
+	}{
+		{
+			name:       "free capacity is taken in table order",
+			capacities: []float64{1, 1},
+			tasks:      []taskSpec{{fn: "BE", manual: []float64{1.5}}},
+			want:       [][]float64{{1}, {0.5}},
+		},
+		{
+			name:       "overload is spread evenly without free capacity",
+			capacities: []float64{1, 1},
+			tasks:      []taskSpec{{fn: "BE", manual: []float64{2}}, {fn: "BE", manual: []float64{1}}},
+			want:       [][]float64{{1.5}, {1.5}},
+		},
+		{
+			name:       "overload is spread in proportion to free capacity",
+			capacities: []float64{1, 3},
+			tasks:      []taskSpec{{fn: "BE", manual: []float64{1}}, {fn: "BE", manual: []float64{6}}},
+			want:       [][]float64{{1}, {6}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources := make([]models.Resource, len(tt.capacities))
+			for i, capacity := range tt.capacities {
+				resources[i] = testResource("BE", "", capacity, capacity)
+			}
+			result := Compute(&models.DataResponse{Resources: resources, Tasks: testTasks(tt.tasks)})
+
+			for i, want := range tt.want {
+				load := make([]float64, result.Calendar.Weeks)
+				copy(load, want)
+				if got := result.Load[resources[i].ID]; !reflect.DeepEqual(got, load) {
+					t.Errorf("resource %d: load %v, want %v", i, got, load)
+				}
+			}
+		})
+	}
+}
+
+func weekNumber(week *int) int {
+	if week == nil {
+		return 0
+	}
+	return *week
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"roadmap/internal/models"
+)
+
+const apiTokenColumns = `id, document_id, name, token_prefix, scope, role, team_ids, service_user_id,
+	created_by, created_at, expires_at, last_used_at, revoked_at`
+
+// GetAPITokens returns all tokens of the document including revoked ones,
+// newest first
+func (r *Repository) GetAPITokens(documentID uuid.UUID) ([]models.APIToken, error) {
+	rows, err := r.db.Query(`
+		SELECT `+apiTokenColumns+`
+		FROM api_tokens
+		WHERE document_id = $1
+		ORDER BY created_at DESC
+	`, documentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []models.APIToken{}
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+// CreateAPIToken stores the token with the hash of its secret, registers its
+// service user and grants the token role to it. It returns sql.ErrNoRows when
+// one of the teams does not belong to the document.
+func (r *Repository) CreateAPIToken(token *models.APIToken, hash string, user *models.User) (*models.APIToken, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if len(token.TeamIDs) > 0 {
+		var found int
+		err := tx.QueryRow(
+			"SELECT COUNT(*) FROM teams WHERE document_id = $1 AND id = ANY($2)", token.DocumentID, pq.Array(token.TeamIDs),
+		).Scan(&found)
+		if err != nil {
+			return nil, err
+		}
+		if found != len(token.TeamIDs) {
+			return nil, sql.ErrNoRows
+		}
+	}
+
+	var teamIDs interface{}
+	if token.TeamIDs != nil {
+		teamIDs = pq.Array(token.TeamIDs)
+	}
+	created, err := scanAPIToken(tx.QueryRow(`
+		INSERT INTO api_tokens (document_id, name, token_hash, token_prefix, scope, role, team_ids,
+			service_user_id, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10)
+		RETURNING `+apiTokenColumns,
+		token.DocumentID, token.Name, hash, token.Prefix, token.Scope, token.Role, teamIDs,
+		token.ServiceUserID, valueOrEmpty(token.CreatedBy), token.ExpiresAt))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO users (id, display_name, avatar_color)
+		VALUES ($1, $2, $3)
+	`, user.ID, user.DisplayName, user.AvatarColor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read tokens see the whole roadmap as viewers
+	role := models.RoleViewer
+	if token.Role != nil {
+		role = *token.Role
+	}
+	grants := []*uuid.UUID{nil}
+	if len(token.TeamIDs) > 0 {
+		grants = grants[:0]
+		for i := range token.TeamIDs {
+			grants = append(grants, &token.TeamIDs[i])
+		}
+	}
+	for _, teamID := range grants {
+		_, err := tx.Exec(`
+			INSERT INTO user_roles (document_id, user_id, role, team_id, created_by)
+			VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		`, token.DocumentID, token.ServiceUserID, role, teamID, valueOrEmpty(token.CreatedBy))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetActiveAPIToken returns the token with the secret hash unless it was
+// revoked or expired, or sql.ErrNoRows
+func (r *Repository) GetActiveAPIToken(hash string) (*models.APIToken, error) {
+	return scanAPIToken(r.db.QueryRow(`
+		SELECT `+apiTokenColumns+`
+		FROM api_tokens
+		WHERE token_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+	`, hash))
+}
+
+// TouchAPIToken records the use of the token, at most once a minute
+func (r *Repository) TouchAPIToken(id uuid.UUID) error {
+	_, err := r.db.Exec(`
+		UPDATE api_tokens SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+	`, id)
+	return err
+}
+
+// RevokeAPIToken revokes an active token and removes the roles of its service
+// user. It returns sql.ErrNoRows when no active token exists.
+func (r *Repository) RevokeAPIToken(documentID, id uuid.UUID) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var serviceUserID string
+	err = tx.QueryRow(`
+		UPDATE api_tokens SET revoked_at = NOW()
+		WHERE document_id = $1 AND id = $2 AND revoked_at IS NULL
+		RETURNING service_user_id
+	`, documentID, id).Scan(&serviceUserID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("DELETE FROM user_roles WHERE document_id = $1 AND user_id = $2", documentID, serviceUserID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func scanAPIToken(row rowScanner) (*models.APIToken, error) {
+	var token models.APIToken
+	var role, createdBy sql.NullString
+	var teamIDs pq.StringArray
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+
+	err := row.Scan(
+		&token.ID, &token.DocumentID, &token.Name, &token.Prefix, &token.Scope, &role, &teamIDs,
+		&token.ServiceUserID, &createdBy, &token.CreatedAt, &expiresAt, &lastUsedAt, &revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if role.Valid {
+		tokenRole := models.Role(role.String)
+		token.Role = &tokenRole
+	}
+	for _, value := range teamIDs {
+		if id, err := uuid.Parse(value); err == nil {
+			token.TeamIDs = append(token.TeamIDs, id)
+		}
+	}
+	if createdBy.Valid {
+		token.CreatedBy = &createdBy.String
+	}
+	if expiresAt.Valid {
+		token.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return &token, nil
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
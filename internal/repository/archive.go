@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"roadmap/internal/models"
+)
+
+// ErrNotEmpty is returned when restoring into a document that has rows
+// without replacing them
+var ErrNotEmpty = errors.New("roadmap is not empty")
+
+// ErrIDConflict is returned when restored rows have ids of rows of another document
+var ErrIDConflict = errors.New("ids are used by another roadmap")
+
+// RestoreResult counts what restoring an archive removed from the document
+type RestoreResult struct {
+	Deleted      int // Rows deleted by replace
+	RevokedRoles int // Team role grants removed with teams missing from the archive
+}
+
+// RestoreArchive writes the rows of the archive into the document in one
+// transaction and change set. With replace the rows of the document are
+// deleted first, except teams that are in the archive: they are updated in
+// place, so that role grants for them survive. Otherwise the document must be
+// empty. Fields are written as archived, missing ones as NULL like in
+// UpdateData; resources and tasks are linked in the order of the archive.
+func (r *Repository) RestoreArchive(documentID uuid.UUID, archive *models.Archive, replace bool, userID string, meta *models.ChangeSetMeta) (*RestoreResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Concurrent updates of the document wait until the restore is committed
+	if _, err := tx.Exec("SELECT id FROM document_versions WHERE id = $1 FOR UPDATE", documentID); err != nil {
+		return nil, err
+	}
+	if err := startChangeSet(tx, documentID, userID, meta); err != nil {
+		return nil, err
+	}
+
+	teamIDs := make([]uuid.UUID, len(archive.Teams))
+	for i, team := range archive.Teams {
+		teamIDs[i] = team.ID
+	}
+
+	result := &RestoreResult{}
+	// Rows referencing teams go first
+	for _, table := range []string{"tasks", "resources", "sprints", "teams"} {
+		if !replace {
+			var exists bool
+			err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM "+table+" WHERE document_id = $1)", documentID).Scan(&exists)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				return nil, fmt.Errorf("document has %s: %w", table, ErrNotEmpty)
+			}
+			continue
+		}
+
+		query, args := "DELETE FROM "+table+" WHERE document_id = $1", []interface{}{documentID}
+		if table == "teams" {
+			// Grants for deleted teams are removed by ON DELETE CASCADE
+			err := tx.QueryRow(`
+				SELECT COUNT(*) FROM user_roles
+				WHERE document_id = $1 AND team_id IN (
+					SELECT id FROM teams WHERE document_id = $1 AND NOT (id = ANY($2))
+				)
+			`, documentID, pq.Array(teamIDs)).Scan(&result.RevokedRoles)
+			if err != nil {
+				return nil, err
+			}
+			query, args = query+" AND NOT (id = ANY($2))", append(args, pq.Array(teamIDs))
+		}
+		deleted, err := tx.Exec(query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete %s: %w", table, err)
+		}
+		affected, err := deleted.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		result.Deleted += int(affected)
+	}
+
+	if err := checkArchiveIDs(tx, documentID, archive); err != nil {
+		return nil, err
+	}
+
+	// Teams kept by replace are overwritten with their archived fields
+	for _, team := range archive.Teams {
+		_, err := tx.Exec(`
+			INSERT INTO teams (id, name, jira_project, feature_team, issue_type, created_at, document_id)
+			VALUES ($1, $2, $3, $4, $5, COALESCE($6, NOW()), $7)
+			ON CONFLICT (id) DO UPDATE SET
+				name = EXCLUDED.name,
+				jira_project = EXCLUDED.jira_project,
+				feature_team = EXCLUDED.feature_team,
+				issue_type = EXCLUDED.issue_type,
+				created_at = EXCLUDED.created_at
+		`, team.ID, team.Name, team.JiraProject, team.FeatureTeam, team.IssueType, createdAt(team.CreatedAt), documentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore team %s: %w", team.ID, err)
+		}
+	}
+
+	for _, sprint := range archive.Sprints {
+		_, err := tx.Exec(`
+			INSERT INTO sprints (id, code, start_date, end_date, created_at, document_id)
+			VALUES ($1, $2, $3, $4, COALESCE($5, NOW()), $6)
+		`, sprint.ID, sprint.Code, sprint.StartDate, sprint.EndDate, createdAt(sprint.CreatedAt), documentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore sprint %s: %w", sprint.ID, err)
+		}
+	}
+
+	// Rows are linked to the previous one on insert and the previous one to
+	// them afterwards, as the foreign keys require the neighbour to exist
+	for i, resource := range archive.Resources {
+		var prevID *uuid.UUID
+		if i > 0 {
+			prevID = &archive.Resources[i-1].ID
+		}
+		_, err := tx.Exec(`
+			INSERT INTO resources (id, team_ids, function, employee, fn_bg_color, fn_text_color, weeks, prev_id, created_at, document_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, NOW()), $10)
+		`, resource.ID, nullableArray(resource.TeamIDs), resource.Function, resource.Employee,
+			resource.FnBgColor, resource.FnTextColor, nullableArray(resource.Weeks), prevID,
+			createdAt(resource.CreatedAt), documentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore resource %s: %w", resource.ID, err)
+		}
+	}
+
+	for i, task := range archive.Tasks {
+		var prevID *uuid.UUID
+		if i > 0 {
+			prevID = &archive.Tasks[i-1].ID
+		}
+		_, err := tx.Exec(`
+			INSERT INTO tasks (
+				id, status, sprints_auto, epic, task_name, team_id, function, employee,
+				plan_empl, plan_weeks, blocker_ids, week_blockers, fact, start_week, end_week,
+				expected_start_week, auto_plan_enabled, weeks, prev_id, created_at, document_id
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, COALESCE($20, NOW()), $21)
+		`, task.ID, task.Status, nullableArray(task.SprintsAuto), task.Epic, task.TaskName, task.TeamID,
+			task.Function, task.Employee, task.PlanEmpl, task.PlanWeeks,
+			nullableArray(task.BlockerIDs), nullableArray(task.WeekBlockers), task.Fact,
+			task.StartWeek, task.EndWeek, task.ExpectedStartWeek, task.AutoPlanEnabled,
+			nullableArray(task.Weeks), prevID, createdAt(task.CreatedAt), documentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore task %s: %w", task.ID, err)
+		}
+	}
+
+	for _, table := range []string{"resources", "tasks"} {
+		_, err := tx.Exec(`
+			UPDATE `+table+` AS r SET next_id = n.id
+			FROM `+table+` AS n
+			WHERE n.prev_id = r.id AND r.document_id = $1 AND n.document_id = $1
+		`, documentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to link %s: %w", table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// checkArchiveIDs returns ErrIDConflict when a row of the archive has the id
+// of a row of another document; ids are unique across documents
+func checkArchiveIDs(tx *sql.Tx, documentID uuid.UUID, archive *models.Archive) error {
+	ids := map[string][]uuid.UUID{}
+	for _, team := range archive.Teams {
+		ids["teams"] = append(ids["teams"], team.ID)
+	}
+	for _, sprint := range archive.Sprints {
+		ids["sprints"] = append(ids["sprints"], sprint.ID)
+	}
+	for _, resource := range archive.Resources {
+		ids["resources"] = append(ids["resources"], resource.ID)
+	}
+	for _, task := range archive.Tasks {
+		ids["tasks"] = append(ids["tasks"], task.ID)
+	}
+
+	for _, table := range []string{"teams", "sprints", "resources", "tasks"} {
+		if len(ids[table]) == 0 {
+			continue
+		}
+		var conflicts int
+		err := tx.QueryRow(
+			"SELECT COUNT(*) FROM "+table+" WHERE id = ANY($1) AND document_id <> $2",
+			pq.Array(ids[table]), documentID,
+		).Scan(&conflicts)
+		if err != nil {
+			return err
+		}
+		if conflicts > 0 {
+			return fmt.Errorf("%d %s: %w", conflicts, table, ErrIDConflict)
+		}
+	}
+	return nil
+}
+
+// nullableArray passes a nil array as NULL and any other as a Postgres array
+func nullableArray[T any](values *T) interface{} {
+	if values == nil {
+		return nil
+	}
+	return pq.Array(*values)
+}
+
+// createdAt passes a zero creation time as NULL, so that the column default applies
+func createdAt(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
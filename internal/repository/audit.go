@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"roadmap/internal/models"
+)
+
+// auditEntries selects change_log rows of the document matching the conditions
+// over cl, at most limit of them when set, with the columns changed by each
+// UPDATE against its old data. Updates logged before 017 have none and are
+// compared with the previous logged state of their record instead; without
+// one every column counts as changed.
+func auditEntries(where []string, limit string) string {
+	query := `
+	WITH selected AS (
+		SELECT cl.*
+		FROM change_log cl
+		WHERE ` + strings.Join(where, " AND ")
+	if limit != "" {
+		query += `
+		ORDER BY cl.version_number DESC
+		LIMIT ` + limit
+	}
+	return query + `
+	), entries AS (
+		SELECT s.*,
+			CASE WHEN s.operation = 'UPDATE' THEN ARRAY(
+				SELECT n.key
+				FROM jsonb_each(s.new_data) n
+				WHERE n.key <> 'updated_at' AND n.value IS DISTINCT FROM s.prev_data -> n.key
+				ORDER BY n.key
+			) END AS changed_columns
+		FROM (
+			SELECT sel.*, COALESCE(sel.old_data, (
+				SELECT COALESCE(p.new_data, p.old_data)
+				FROM change_log p
+				WHERE p.document_id = sel.document_id AND p.table_name = sel.table_name AND p.record_id = sel.record_id
+					AND p.version_number < sel.version_number
+				ORDER BY p.version_number DESC
+				LIMIT 1
+			)) AS prev_data
+			FROM selected sel
+		) s
+	)`
+}
+
+// GetAuditEntries returns change_log entries of the document matching the
+// filter, newest first, with change set attribution
+func (r *Repository) GetAuditEntries(documentID uuid.UUID, filter *models.AuditFilter) ([]models.AuditEntry, bool, error) {
+	where, columns, args := auditConditions(documentID, filter)
+	if filter.Cursor != nil {
+		args = append(args, *filter.Cursor)
+		where = append(where, fmt.Sprintf("cl.version_number < $%d", len(args)))
+	}
+	args = append(args, filter.Limit+1)
+	limit := "$" + fmt.Sprint(len(args))
+
+	// The page can be cut before comparing states unless it is filtered by them
+	selected := auditEntries(where, limit)
+	if len(columns) > 0 {
+		selected = auditEntries(where, "")
+	}
+	rows, err := r.db.Query(selected+`
+		SELECT e.id, e.version_number, e.table_name, e.record_id, e.operation, e.user_id, e.change_set_id,
+			e.old_data, e.new_data, e.created_at, e.changed_columns,
+			cs.request_id, cs.client_ip, cs.user_agent, cs.note
+		FROM entries e
+		LEFT JOIN change_sets cs ON cs.id = e.change_set_id
+		WHERE `+strings.Join(append([]string{"TRUE"}, columns...), " AND ")+`
+		ORDER BY e.version_number DESC
+		LIMIT `+limit, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	var entries []models.AuditEntry
+	for rows.Next() {
+		var entry models.AuditEntry
+		var userID, requestID, clientIP, userAgent, note sql.NullString
+		var changeSetID uuid.NullUUID
+		var oldData, newData sql.NullString
+		var changedColumns pq.StringArray
+
+		err := rows.Scan(
+			&entry.ID, &entry.VersionNumber, &entry.TableName, &entry.RecordID, &entry.Operation,
+			&userID, &changeSetID, &oldData, &newData, &entry.CreatedAt, &changedColumns,
+			&requestID, &clientIP, &userAgent, &note,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if userID.Valid {
+			entry.UserID = &userID.String
+		}
+		if changeSetID.Valid {
+			entry.ChangeSetID = &changeSetID.UUID
+		}
+		if oldData.Valid {
+			var data interface{}
+			if err := json.Unmarshal([]byte(oldData.String), &data); err == nil {
+				entry.OldData = data
+			}
+		}
+		if newData.Valid {
+			var data interface{}
+			if err := json.Unmarshal([]byte(newData.String), &data); err == nil {
+				entry.NewData = data
+			}
+		}
+		entry.ChangedFields = changedColumns
+		if requestID.Valid {
+			entry.RequestID = &requestID.String
+		}
+		if clientIP.Valid {
+			entry.ClientIP = &clientIP.String
+		}
+		if userAgent.Valid {
+			entry.UserAgent = &userAgent.String
+		}
+		if note.Valid {
+			entry.Note = &note.String
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(entries) > filter.Limit
+	if hasMore {
+		entries = entries[:filter.Limit]
+	}
+	return entries, hasMore, nil
+}
+
+// GetAuditSummary returns the number of changes and change sets per user per
+// day (UTC) for entries matching the filter
+func (r *Repository) GetAuditSummary(documentID uuid.UUID, filter *models.AuditFilter) ([]models.AuditSummaryRow, error) {
+	where, columns, args := auditConditions(documentID, filter)
+
+	rows, err := r.db.Query(auditEntries(where, "")+`
+		SELECT to_char(date_trunc('day', e.created_at AT TIME ZONE 'UTC'), 'YYYY-MM-DD') AS day,
+			e.user_id, COUNT(*), COUNT(DISTINCT e.change_set_id)
+		FROM entries e
+		WHERE `+strings.Join(append([]string{"TRUE"}, columns...), " AND ")+`
+		GROUP BY day, e.user_id
+		ORDER BY day DESC, e.user_id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summary := []models.AuditSummaryRow{}
+	for rows.Next() {
+		var row models.AuditSummaryRow
+		var userID sql.NullString
+		if err := rows.Scan(&row.Day, &userID, &row.Changes, &row.ChangeSets); err != nil {
+			return nil, err
+		}
+		if userID.Valid {
+			row.UserID = &userID.String
+		}
+		summary = append(summary, row)
+	}
+	return summary, rows.Err()
+}
+
+// auditConditions builds the WHERE conditions over change_log cl and the ones
+// over changed columns of the entries CTE; $1 is the document
+func auditConditions(documentID uuid.UUID, filter *models.AuditFilter) ([]string, []string, []interface{}) {
+	where := []string{"cl.document_id = $1"}
+	var columns []string
+	args := []interface{}{documentID}
+	add := func(conditions *[]string, condition string, arg interface{}) {
+		args = append(args, arg)
+		*conditions = append(*conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.UserID != "" {
+		add(&where, "cl.user_id = $%d", filter.UserID)
+	}
+	if filter.Table != "" {
+		add(&where, "cl.table_name = $%d", filter.Table)
+	}
+	if filter.Operation != "" {
+		add(&where, "cl.operation = $%d", filter.Operation)
+	}
+	if filter.RecordID != nil {
+		add(&where, "cl.record_id = $%d", *filter.RecordID)
+	}
+	if filter.Column != "" {
+		add(&columns, "$%d = ANY(e.changed_columns)", filter.Column)
+	}
+	if filter.From != nil {
+		add(&where, "cl.created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add(&where, "cl.created_at < $%d", *filter.To)
+	}
+	return where, columns, args
+}
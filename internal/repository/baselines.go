@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+)
+
+const baselineColumns = `id, name, version, created_by, created_at`
+
+// GetBaselines returns all baselines of the document, newest version first
+func (r *Repository) GetBaselines(documentID uuid.UUID) ([]models.Baseline, error) {
+	rows, err := r.db.Query(`
+		SELECT `+baselineColumns+`
+		FROM baselines
+		WHERE document_id = $1
+		ORDER BY version DESC, created_at DESC
+	`, documentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var baselines []models.Baseline
+	for rows.Next() {
+		baseline, err := scanBaseline(rows)
+		if err != nil {
+			return nil, err
+		}
+		baselines = append(baselines, *baseline)
+	}
+
+	return baselines, rows.Err()
+}
+
+// GetBaseline returns a baseline of the document
+func (r *Repository) GetBaseline(documentID, id uuid.UUID) (*models.Baseline, error) {
+	row := r.db.QueryRow(`
+		SELECT `+baselineColumns+`
+		FROM baselines
+		WHERE document_id = $1 AND id = $2
+	`, documentID, id)
+	return scanBaseline(row)
+}
+
+// CreateBaseline stores a named pointer to a document version
+func (r *Repository) CreateBaseline(documentID uuid.UUID, name string, version int64, createdBy string) (*models.Baseline, error) {
+	row := r.db.QueryRow(`
+		INSERT INTO baselines (document_id, name, version, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+baselineColumns,
+		documentID, name, version, createdBy)
+	return scanBaseline(row)
+}
+
+// DeleteBaseline removes a baseline; the plan itself is not affected.
+// It returns sql.ErrNoRows when the baseline does not exist.
+func (r *Repository) DeleteBaseline(documentID, id uuid.UUID) error {
+	result, err := r.db.Exec("DELETE FROM baselines WHERE document_id = $1 AND id = $2", documentID, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanBaseline(row rowScanner) (*models.Baseline, error) {
+	var baseline models.Baseline
+	var createdBy sql.NullString
+
+	err := row.Scan(&baseline.ID, &baseline.Name, &baseline.Version, &createdBy, &baseline.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if createdBy.Valid {
+		baseline.CreatedBy = &createdBy.String
+	}
+	return &baseline, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+)
+
+// AnnotateUnattributedChanges groups change_log rows that have neither an
+// author nor a change set into one change set per document carrying the note.
+// Already annotated rows are skipped, so running it again is a no-op.
+func (r *Repository) AnnotateUnattributedChanges(note string) ([]models.AttributionGap, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT document_id, MIN(version_number), MAX(version_number), MIN(created_at), MAX(created_at), COUNT(*)
+		FROM change_log
+		WHERE user_id IS NULL AND change_set_id IS NULL AND document_id IS NOT NULL
+		GROUP BY document_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find unattributed changes: %w", err)
+	}
+	var gaps []models.AttributionGap
+	for rows.Next() {
+		var gap models.AttributionGap
+		err := rows.Scan(&gap.DocumentID, &gap.FromVersion, &gap.ToVersion, &gap.StartedAt, &gap.EndedAt, &gap.Changes)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		gaps = append(gaps, gap)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range gaps {
+		gap := &gaps[i]
+		err := tx.QueryRow(`
+			INSERT INTO change_sets (document_id, note, created_at)
+			VALUES ($1, $2, $3)
+			RETURNING id
+		`, gap.DocumentID, note, gap.StartedAt).Scan(&gap.ChangeSetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create annotation for roadmap %s: %w", gap.DocumentID, err)
+		}
+
+		_, err = tx.Exec(`
+			UPDATE change_log
+			SET change_set_id = $2
+			WHERE document_id = $1 AND user_id IS NULL AND change_set_id IS NULL
+		`, gap.DocumentID, gap.ChangeSetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to annotate changes of roadmap %s: %w", gap.DocumentID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return gaps, nil
+}
+
+// startChangeSet records a change set and stores it with the user in session
+// variables, so the log trigger attributes every change of the transaction
+func startChangeSet(tx *sql.Tx, documentID uuid.UUID, userID string, meta *models.ChangeSetMeta) error {
+	// Use string concatenation for SET LOCAL as it doesn't support parameters
+	// But validate the UUID to prevent SQL injection
+	if _, err := uuid.Parse(userID); err != nil {
+		return fmt.Errorf("invalid user_id format: %w", err)
+	}
+	if _, err := tx.Exec("SET LOCAL app.user_id = '" + userID + "'"); err != nil {
+		return fmt.Errorf("failed to set user_id: %w", err)
+	}
+
+	var changeSetID uuid.UUID
+	err := tx.QueryRow(`
+		INSERT INTO change_sets (document_id, user_id, request_id, client_ip, user_agent, api_token_id, note)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), $6, NULLIF($7, ''))
+		RETURNING id
+	`, documentID, userID, meta.RequestID, meta.ClientIP, meta.UserAgent, meta.APITokenID, meta.Note).Scan(&changeSetID)
+	if err != nil {
+		return fmt.Errorf("failed to record change set: %w", err)
+	}
+	if _, err := tx.Exec("SELECT set_config('app.change_set_id', $1, true)", changeSetID.String()); err != nil {
+		return fmt.Errorf("failed to set change_set_id: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+)
+
+// CompactChangeLog snapshots the document at the newest version logged before
+// the cutoff time and prunes change_log up to that version. Baselines inside
+// the pruned range get their own snapshots so they stay readable. Returns nil
+// when there is nothing to compact.
+func (r *Repository) CompactChangeLog(documentID uuid.UUID, before time.Time) (*models.CompactionResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Locking the version row blocks writers at their log trigger, so the
+	// snapshot and the pruned range stay consistent
+	var logStart int64
+	err = tx.QueryRow(
+		"SELECT log_start_version FROM document_versions WHERE id = $1 FOR UPDATE", documentID,
+	).Scan(&logStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock document: %w", err)
+	}
+
+	var cutoff int64
+	err = tx.QueryRow(`
+		SELECT COALESCE(MAX(version_number), 0)
+		FROM change_log
+		WHERE document_id = $1 AND created_at < $2
+	`, documentID, before).Scan(&cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find compaction cutoff: %w", err)
+	}
+	if cutoff <= logStart {
+		return nil, nil
+	}
+
+	versions := []int64{}
+	rows, err := tx.Query(`
+		SELECT DISTINCT version
+		FROM baselines
+		WHERE document_id = $1 AND version >= $2 AND version < $3
+		ORDER BY version
+	`, documentID, logStart, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get baselines: %w", err)
+	}
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	versions = append(versions, cutoff)
+
+	for _, version := range versions {
+		snapshot, err := r.rowsAtVersion(tx, documentID, version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconstruct version %d: %w", version, err)
+		}
+		data, err := json.Marshal(snapshot)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.Exec(`
+			INSERT INTO document_snapshots (document_id, version, data)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (document_id, version) DO NOTHING
+		`, documentID, version, string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to save snapshot at version %d: %w", version, err)
+		}
+	}
+
+	result, err := tx.Exec("DELETE FROM change_log WHERE document_id = $1 AND version_number <= $2", documentID, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prune change log: %w", err)
+	}
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("UPDATE document_versions SET log_start_version = $2 WHERE id = $1", documentID, cutoff); err != nil {
+		return nil, fmt.Errorf("failed to move log start: %w", err)
+	}
+
+	// Keep the base snapshot and snapshots of baselines only
+	_, err = tx.Exec(`
+		DELETE FROM document_snapshots
+		WHERE document_id = $1 AND version < $2
+		  AND version NOT IN (SELECT version FROM baselines WHERE document_id = $1)
+	`, documentID, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to drop old snapshots: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &models.CompactionResult{
+		DocumentID:      documentID,
+		SnapshotVersion: cutoff,
+		PrunedChanges:   pruned,
+	}, nil
+}
@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+)
+
+// ErrHistoryPruned is returned when the requested version predates the
+// change_log retention window and no snapshot exists for it
+var ErrHistoryPruned = errors.New("history pruned")
+
+// LoggedRows holds rows of a document in the change_log JSON format (column
+// names as keys) as table -> id -> row
+type LoggedRows map[string]map[uuid.UUID]interface{}
+
+// RecordStateChange holds the logged state of a record at two versions; a nil
+// state means the record did not exist
+type RecordStateChange struct {
+	Table    string
+	RecordID uuid.UUID
+	Before   interface{}
+	After    interface{}
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// loggedTables are the tables whose changes are recorded in change_log
+var loggedTables = []string{"teams", "sprints", "resources", "tasks"}
+
+// GetRowsAtVersion reconstructs all rows of the document at the given version.
+// Every record changed after the version is reset to its last logged state at
+// or before it, falling back to the compaction snapshot. Versions before the
+// retention window are only available when a snapshot was kept for them.
+func (r *Repository) GetRowsAtVersion(documentID uuid.UUID, version int64) (LoggedRows, error) {
+	return r.rowsAtVersion(r.db, documentID, version)
+}
+
+// GetRecordChanges returns the state before and after of every record changed
+// between the two versions, in order of the first change
+func (r *Repository) GetRecordChanges(documentID uuid.UUID, fromVersion, toVersion int64) ([]RecordStateChange, error) {
+	logStart, err := r.getLogStartVersion(r.db, documentID)
+	if err != nil {
+		return nil, err
+	}
+	if fromVersion < logStart {
+		return nil, ErrHistoryPruned
+	}
+
+	history, err := r.recordHistory(r.db, documentID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	base, err := r.compactionBase(r.db, documentID, logStart)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[recordKey]bool)
+	for _, change := range history {
+		if change.VersionNumber > fromVersion && change.VersionNumber <= toVersion {
+			changed[recordKey{change.TableName, change.RecordID}] = true
+		}
+	}
+
+	before := recordStates(history, fromVersion, base)
+	after := recordStates(history, toVersion, base)
+	var changes []RecordStateChange
+	for i, record := range before {
+		if !changed[record.recordKey] {
+			continue
+		}
+		changes = append(changes, RecordStateChange{
+			Table:    record.table,
+			RecordID: record.id,
+			Before:   record.state,
+			After:    after[i].state,
+		})
+	}
+	return changes, nil
+}
+
+func (r *Repository) rowsAtVersion(q queryer, documentID uuid.UUID, version int64) (LoggedRows, error) {
+	var logStart, currentVersion int64
+	err := q.QueryRow(
+		"SELECT log_start_version, version_number FROM document_versions WHERE id = $1", documentID,
+	).Scan(&logStart, &currentVersion)
+	if err != nil {
+		return nil, err
+	}
+	if version > currentVersion {
+		return nil, fmt.Errorf("version %d is newer than the current version %d", version, currentVersion)
+	}
+	if version < logStart {
+		rows, err := r.snapshotRows(q, documentID, version)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrHistoryPruned
+		}
+		return rows, err
+	}
+
+	rows, err := r.currentRows(q, documentID)
+	if err != nil {
+		return nil, err
+	}
+	if version == currentVersion {
+		return rows, nil
+	}
+
+	history, err := r.recordHistory(q, documentID, version)
+	if err != nil {
+		return nil, err
+	}
+	base, err := r.compactionBase(q, documentID, logStart)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range recordStates(history, version, base) {
+		if record.state == nil {
+			delete(rows[record.table], record.id)
+		} else if rows[record.table] != nil {
+			rows[record.table][record.id] = record.state
+		}
+	}
+	return rows, nil
+}
+
+// currentRows returns all current rows of the document as the trigger logs them
+func (r *Repository) currentRows(q queryer, documentID uuid.UUID) (LoggedRows, error) {
+	rows := make(LoggedRows, len(loggedTables))
+	for _, table := range loggedTables {
+		rows[table] = make(map[uuid.UUID]interface{})
+
+		// Table names come from the fixed list above
+		result, err := q.Query(`SELECT id, to_jsonb(t) FROM `+table+` t WHERE document_id = $1`, documentID)
+		if err != nil {
+			return nil, err
+		}
+		for result.Next() {
+			var id uuid.UUID
+			var data []byte
+			if err := result.Scan(&id, &data); err != nil {
+				result.Close()
+				return nil, err
+			}
+			var row interface{}
+			if err := json.Unmarshal(data, &row); err != nil {
+				result.Close()
+				return nil, err
+			}
+			rows[table][id] = row
+		}
+		err = result.Err()
+		result.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+// recordHistory returns the full retained change history of every record of
+// the document that was changed after the specified version, oldest first
+func (r *Repository) recordHistory(q queryer, documentID uuid.UUID, afterVersion int64) ([]models.ChangeLog, error) {
+	rows, err := q.Query(`
+		SELECT `+changeLogColumns+`
+		FROM change_log
+		WHERE document_id = $1 AND (table_name, record_id) IN (
+			SELECT table_name, record_id
+			FROM change_log
+			WHERE document_id = $1 AND version_number > $2
+		)
+		ORDER BY version_number, created_at
+	`, documentID, afterVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanChanges(rows)
+}
+
+// snapshotRows returns the rows stored by compaction at exactly the version
+func (r *Repository) snapshotRows(q queryer, documentID uuid.UUID, version int64) (LoggedRows, error) {
+	var data []byte
+	err := q.QueryRow(
+		"SELECT data FROM document_snapshots WHERE document_id = $1 AND version = $2", documentID, version,
+	).Scan(&data)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows LoggedRows
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot at version %d: %w", version, err)
+	}
+	return rows, nil
+}
+
+// compactionBase returns the snapshot at the start of the retained log, or nil
+// when the log was never compacted
+func (r *Repository) compactionBase(q queryer, documentID uuid.UUID, logStart int64) (LoggedRows, error) {
+	if logStart == 0 {
+		return nil, nil
+	}
+	rows, err := r.snapshotRows(q, documentID, logStart)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return rows, err
+}
+
+// GetLogStartVersion returns the version up to which change_log was compacted;
+// changes after it are retained
+func (r *Repository) GetLogStartVersion(documentID uuid.UUID) (int64, error) {
+	return r.getLogStartVersion(r.db, documentID)
+}
+
+func (r *Repository) getLogStartVersion(q queryer, documentID uuid.UUID) (int64, error) {
+	var logStart int64
+	err := q.QueryRow("SELECT log_start_version FROM document_versions WHERE id = $1", documentID).Scan(&logStart)
+	return logStart, err
+}
+
+// recordKey identifies a record in change_log
+type recordKey struct {
+	table string
+	id    uuid.UUID
+}
+
+type recordState struct {
+	recordKey
+	state interface{}
+}
+
+// recordStates returns the logged state of every record of the history at the
+// version (nil when the record did not exist) in first-seen order. Records
+// without retained changes at or before the version take their state from the
+// compaction snapshot, otherwise from the old data of their first change after
+// the version.
+func recordStates(history []models.ChangeLog, version int64, base LoggedRows) []recordState {
+	var states []recordState
+	index := make(map[recordKey]int)
+	for _, change := range history {
+		key := recordKey{change.TableName, change.RecordID}
+		i, known := index[key]
+		if !known {
+			i = len(states)
+			index[key] = i
+			states = append(states, recordState{recordKey: key})
+		}
+		switch {
+		case change.VersionNumber <= version:
+			// Latest state at or before the version; DELETE leaves nil
+			states[i].state = change.NewData
+		case known:
+			// The state at the version is already settled
+		case base != nil:
+			states[i].state = base[key.table][key.id]
+		case change.Operation == "INSERT":
+			// First change after the version: the record was absent before
+			states[i].state = nil
+		case change.OldData != nil:
+			states[i].state = change.OldData
+		default:
+			// Updates logged before 017 have no old data; the closest logged
+			// state is the best we have
+			states[i].state = change.NewData
+		}
+	}
+	return states
+}
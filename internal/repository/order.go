@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+)
+
+// RelinkRows rewrites prev_id/next_id of the resources or tasks so that they
+// form one list in the given order, in one change set. Unlike UpdateData it
+// can clear links, which a broken list needs at its ends. Returns the number
+// of rows whose links changed.
+func (r *Repository) RelinkRows(documentID uuid.UUID, table string, order []uuid.UUID, userID string, meta *models.ChangeSetMeta) (int, error) {
+	if table != "resources" && table != "tasks" {
+		return 0, fmt.Errorf("unknown ordered table: %s", table)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := startChangeSet(tx, documentID, userID, meta); err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for i, id := range order {
+		var prevID, nextID *uuid.UUID
+		if i > 0 {
+			prevID = &order[i-1]
+		}
+		if i < len(order)-1 {
+			nextID = &order[i+1]
+		}
+		result, err := tx.Exec(`
+			UPDATE `+table+` SET prev_id = $3, next_id = $4, updated_at = NOW()
+			WHERE id = $1 AND document_id = $2
+			  AND (prev_id IS DISTINCT FROM $3 OR next_id IS DISTINCT FROM $4)
+		`, id, documentID, prevID, nextID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to relink %s %s: %w", table, id, err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		changed += int(affected)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -11,6 +12,10 @@ import (
 	"roadmap/internal/models"
 )
 
+// ErrOtherDocument is returned when an update writes, deletes or references
+// a record of another document
+var ErrOtherDocument = errors.New("record belongs to another roadmap")
+
 type Repository struct {
 	db *sql.DB
 }
@@ -19,16 +24,99 @@ func New(db *sql.DB) *Repository {
 	return &Repository{db: db}
 }
 
-// GetCurrentVersion returns the current document version
-func (r *Repository) GetCurrentVersion() (int64, error) {
+// GetCurrentVersion returns the current version of the document
+func (r *Repository) GetCurrentVersion(documentID uuid.UUID) (int64, error) {
 	var version int64
-	err := r.db.QueryRow("SELECT version_number FROM document_versions LIMIT 1").Scan(&version)
+	err := r.db.QueryRow("SELECT version_number FROM document_versions WHERE id = $1", documentID).Scan(&version)
 	return version, err
 }
 
-// GetAllData returns all data with the current version
-func (r *Repository) GetAllData() (*models.DataResponse, error) {
-	version, err := r.GetCurrentVersion()
+// GetDefaultDocumentID returns the oldest document, used by the unscoped API routes
+func (r *Repository) GetDefaultDocumentID() (uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.db.QueryRow("SELECT id FROM document_versions ORDER BY created_at LIMIT 1").Scan(&id)
+	return id, err
+}
+
+// GetRoadmaps returns all roadmap documents, or only those the user has a
+// role in when userID is set
+func (r *Repository) GetRoadmaps(userID string) ([]models.Roadmap, error) {
+	rows, err := r.db.Query(`
+		SELECT id, name, version_number, created_at
+		FROM document_versions
+		WHERE $1 = '' OR id IN (SELECT document_id FROM user_roles WHERE user_id = $1)
+		ORDER BY created_at
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roadmaps []models.Roadmap
+	for rows.Next() {
+		var roadmap models.Roadmap
+		if err := rows.Scan(&roadmap.ID, &roadmap.Name, &roadmap.Version, &roadmap.CreatedAt); err != nil {
+			return nil, err
+		}
+		roadmaps = append(roadmaps, roadmap)
+	}
+
+	return roadmaps, rows.Err()
+}
+
+// GetRoadmap returns a single roadmap document
+func (r *Repository) GetRoadmap(id uuid.UUID) (*models.Roadmap, error) {
+	var roadmap models.Roadmap
+	err := r.db.QueryRow(`
+		SELECT id, name, version_number, created_at
+		FROM document_versions
+		WHERE id = $1
+	`, id).Scan(&roadmap.ID, &roadmap.Name, &roadmap.Version, &roadmap.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &roadmap, nil
+}
+
+// CreateRoadmap creates a new empty roadmap document with its own version
+// counter. When admin is set the user is granted the admin role in the same
+// transaction.
+func (r *Repository) CreateRoadmap(name, admin string) (*models.Roadmap, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var roadmap models.Roadmap
+	err = tx.QueryRow(`
+		INSERT INTO document_versions (name, version_number)
+		VALUES ($1, 1)
+		RETURNING id, name, version_number, created_at
+	`, name).Scan(&roadmap.ID, &roadmap.Name, &roadmap.Version, &roadmap.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if admin != "" {
+		_, err = tx.Exec(`
+			INSERT INTO user_roles (document_id, user_id, role, created_by)
+			VALUES ($1, $2, $3, $2)
+		`, roadmap.ID, admin, models.RoleAdmin)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &roadmap, nil
+}
+
+// GetAllData returns all data of the document with its current version
+func (r *Repository) GetAllData(documentID uuid.UUID) (*models.DataResponse, error) {
+	version, err := r.GetCurrentVersion(documentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current version: %w", err)
 	}
@@ -38,28 +126,28 @@ func (r *Repository) GetAllData() (*models.DataResponse, error) {
 	}
 
 	// Get teams
-	teams, err := r.GetTeams()
+	teams, err := r.GetTeams(documentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get teams: %w", err)
 	}
 	response.Teams = teams
 
 	// Get sprints
-	sprints, err := r.GetSprints()
+	sprints, err := r.GetSprints(documentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get sprints: %w", err)
 	}
 	response.Sprints = sprints
 
 	// Get resources
-	resources, err := r.GetResources()
+	resources, err := r.GetResources(documentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get resources: %w", err)
 	}
 	response.Resources = resources
 
 	// Get tasks
-	tasks, err := r.GetTasks()
+	tasks, err := r.GetTasks(documentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
@@ -68,13 +156,14 @@ func (r *Repository) GetAllData() (*models.DataResponse, error) {
 	return response, nil
 }
 
-// GetTeams returns all teams
-func (r *Repository) GetTeams() ([]models.Team, error) {
+// GetTeams returns all teams of the document
+func (r *Repository) GetTeams(documentID uuid.UUID) ([]models.Team, error) {
 	rows, err := r.db.Query(`
 		SELECT id, name, jira_project, feature_team, issue_type, created_at, updated_at 
 		FROM teams 
+		WHERE document_id = $1
 		ORDER BY name
-	`)
+	`, documentID)
 	if err != nil {
 		return nil, err
 	}
@@ -96,13 +185,14 @@ func (r *Repository) GetTeams() ([]models.Team, error) {
 	return teams, rows.Err()
 }
 
-// GetSprints returns all sprints
-func (r *Repository) GetSprints() ([]models.Sprint, error) {
+// GetSprints returns all sprints of the document
+func (r *Repository) GetSprints(documentID uuid.UUID) ([]models.Sprint, error) {
 	rows, err := r.db.Query(`
 		SELECT id, code, start_date, end_date, created_at, updated_at 
 		FROM sprints 
+		WHERE document_id = $1
 		ORDER BY start_date
-	`)
+	`, documentID)
 	if err != nil {
 		return nil, err
 	}
@@ -124,21 +214,22 @@ func (r *Repository) GetSprints() ([]models.Sprint, error) {
 	return sprints, rows.Err()
 }
 
-// GetResources returns all resources ordered by linked list
-func (r *Repository) GetResources() ([]models.Resource, error) {
+// GetResources returns all resources of the document ordered by linked list
+func (r *Repository) GetResources(documentID uuid.UUID) ([]models.Resource, error) {
 	rows, err := r.db.Query(`
 		SELECT
 			id, team_ids, function, employee, fn_bg_color, fn_text_color, weeks,
 			prev_id, next_id, created_at, updated_at
 		FROM resources
-	`)
+		WHERE document_id = $1
+	`, documentID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	// Create maps for team lookups
-	teamMap, err := r.getTeamMap()
+	teamMap, err := r.getTeamMap(documentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get team map: %w", err)
 	}
@@ -268,8 +359,8 @@ func (r *Repository) GetResources() ([]models.Resource, error) {
 	return resources, nil
 }
 
-// GetTasks returns all tasks with populated team names, ordered by linked list
-func (r *Repository) GetTasks() ([]models.Task, error) {
+// GetTasks returns all tasks of the document with populated team names, ordered by linked list
+func (r *Repository) GetTasks(documentID uuid.UUID) ([]models.Task, error) {
 	rows, err := r.db.Query(`
 		SELECT
 			t.id, t.status, t.sprints_auto, t.epic, t.task_name,
@@ -280,7 +371,8 @@ func (r *Repository) GetTasks() ([]models.Task, error) {
 			tm.name as team_name
 		FROM tasks t
 		LEFT JOIN teams tm ON t.team_id = tm.id
-	`)
+		WHERE t.document_id = $1
+	`, documentID)
 	if err != nil {
 		return nil, err
 	}
@@ -447,28 +539,78 @@ func (r *Repository) GetTasks() ([]models.Task, error) {
 	return tasks, nil
 }
 
-// GetChangesSince returns all changes since the specified version
-func (r *Repository) GetChangesSince(fromVersion int64) ([]models.ChangeLog, error) {
-	rows, err := r.db.Query(`
-		SELECT id, version_number, table_name, record_id, operation, user_id, old_data, new_data, created_at
+// GetChangesSince returns changes of the document since the specified version.
+// With a positive limit at most limit changes are returned and hasMore reports
+// whether more follow. It returns ErrHistoryPruned when changes after the
+// version were compacted.
+func (r *Repository) GetChangesSince(documentID uuid.UUID, fromVersion int64, limit int) ([]models.ChangeLog, bool, error) {
+	logStart, err := r.getLogStartVersion(r.db, documentID)
+	if err != nil {
+		return nil, false, err
+	}
+	if fromVersion < logStart {
+		return nil, false, ErrHistoryPruned
+	}
+
+	query := `
+		SELECT `+changeLogColumns+`
 		FROM change_log 
-		WHERE version_number > $1 
+		WHERE document_id = $1 AND version_number > $2 
 		ORDER BY version_number, created_at
-	`, fromVersion)
+	`
+	args := []interface{}{documentID, fromVersion}
+	if limit > 0 {
+		// One extra row tells whether there is a next page
+		query += " LIMIT $3"
+		args = append(args, limit+1)
+	}
+
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer rows.Close()
 
+	changes, err := scanChanges(rows)
+	if err != nil {
+		return nil, false, err
+	}
+	hasMore := limit > 0 && len(changes) > limit
+	if hasMore {
+		changes = changes[:limit]
+	}
+	return changes, hasMore, nil
+}
+
+// GetDiffSize returns the number of changes since the version and the number
+// of rows in the document, i.e. the size of a full data response
+func (r *Repository) GetDiffSize(documentID uuid.UUID, fromVersion int64) (int64, int64, error) {
+	var changes, rows int64
+	err := r.db.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM change_log WHERE document_id = $1 AND version_number > $2),
+			(SELECT COUNT(*) FROM teams WHERE document_id = $1) +
+			(SELECT COUNT(*) FROM sprints WHERE document_id = $1) +
+			(SELECT COUNT(*) FROM resources WHERE document_id = $1) +
+			(SELECT COUNT(*) FROM tasks WHERE document_id = $1)
+	`, documentID, fromVersion).Scan(&changes, &rows)
+	return changes, rows, err
+}
+
+const changeLogColumns = `id, version_number, table_name, record_id, operation, user_id, change_set_id, old_data, new_data, created_at`
+
+// scanChanges reads change_log rows selected with changeLogColumns
+func scanChanges(rows *sql.Rows) ([]models.ChangeLog, error) {
 	var changes []models.ChangeLog
 	for rows.Next() {
 		var change models.ChangeLog
 		var oldData, newData sql.NullString
 		var userID sql.NullString
+		var changeSetID uuid.NullUUID
 
 		err := rows.Scan(
 			&change.ID, &change.VersionNumber, &change.TableName, &change.RecordID,
-			&change.Operation, &userID, &oldData, &newData, &change.CreatedAt,
+			&change.Operation, &userID, &changeSetID, &oldData, &newData, &change.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -477,6 +619,9 @@ func (r *Repository) GetChangesSince(fromVersion int64) ([]models.ChangeLog, err
 		if userID.Valid {
 			change.UserID = &userID.String
 		}
+		if changeSetID.Valid {
+			change.ChangeSetID = &changeSetID.UUID
+		}
 
 		if oldData.Valid {
 			var data interface{}
@@ -499,8 +644,8 @@ func (r *Repository) GetChangesSince(fromVersion int64) ([]models.ChangeLog, err
 }
 
 // Helper function to get team ID to name mapping
-func (r *Repository) getTeamMap() (map[uuid.UUID]string, error) {
-	rows, err := r.db.Query("SELECT id, name FROM teams")
+func (r *Repository) getTeamMap(documentID uuid.UUID) (map[uuid.UUID]string, error) {
+	rows, err := r.db.Query("SELECT id, name FROM teams WHERE document_id = $1", documentID)
 	if err != nil {
 		return nil, err
 	}
@@ -524,59 +669,58 @@ func (r *Repository) BeginTransaction() (*sql.Tx, error) {
 	return r.db.Begin()
 }
 
-// UpdateData updates data in the database within a transaction
-func (r *Repository) UpdateData(tx *sql.Tx, req *models.UpdateRequest) error {
+// UpdateData updates data of the document in the database within a transaction.
+// Rows belonging to another document are never touched.
+func (r *Repository) UpdateData(tx *sql.Tx, documentID uuid.UUID, req *models.UpdateRequest) error {
 	// Set user_id in session variable for triggers
 	fmt.Printf("Repository: Setting user_id to: %s\n", req.UserID)
-	// Use string concatenation for SET LOCAL as it doesn't support parameters
-	// But validate the UUID to prevent SQL injection
-	if _, err := uuid.Parse(req.UserID); err != nil {
-		return fmt.Errorf("invalid user_id format: %w", err)
+	if err := startChangeSet(tx, documentID, req.UserID, &req.Meta); err != nil {
+		return err
 	}
-	_, err := tx.Exec("SET LOCAL app.user_id = '" + req.UserID + "'")
-	if err != nil {
-		fmt.Printf("Repository: Error setting user_id: %v\n", err)
-		return fmt.Errorf("failed to set user_id: %w", err)
+	if err := checkReferences(tx, documentID, req); err != nil {
+		return err
 	}
-	fmt.Println("Repository: user_id set successfully")
+
 	// Update teams
 	for _, team := range req.Teams {
-		_, err := tx.Exec(`
-			INSERT INTO teams (id, name, jira_project, feature_team, issue_type)
-			VALUES ($1, $2, $3, $4, $5)
+		result, err := tx.Exec(`
+			INSERT INTO teams (id, name, jira_project, feature_team, issue_type, document_id)
+			VALUES ($1, $2, $3, $4, $5, $6)
 			ON CONFLICT (id) DO UPDATE SET
 				name = COALESCE(EXCLUDED.name, teams.name),
 				jira_project = COALESCE(EXCLUDED.jira_project, teams.jira_project),
 				feature_team = COALESCE(EXCLUDED.feature_team, teams.feature_team),
 				issue_type = COALESCE(EXCLUDED.issue_type, teams.issue_type),
 				updated_at = NOW()
-		`, team.ID, team.Name, team.JiraProject, team.FeatureTeam, team.IssueType)
-		if err != nil {
+			WHERE teams.document_id = EXCLUDED.document_id
+		`, team.ID, team.Name, team.JiraProject, team.FeatureTeam, team.IssueType, documentID)
+		if err := checkDocumentRow(result, err); err != nil {
 			return fmt.Errorf("failed to update team %s: %w", team.ID, err)
 		}
 	}
 
 	// Update sprints
 	for _, sprint := range req.Sprints {
-		_, err := tx.Exec(`
-			INSERT INTO sprints (id, code, start_date, end_date)
-			VALUES ($1, $2, $3, $4)
+		result, err := tx.Exec(`
+			INSERT INTO sprints (id, code, start_date, end_date, document_id)
+			VALUES ($1, $2, $3, $4, $5)
 			ON CONFLICT (id) DO UPDATE SET
 				code = COALESCE(EXCLUDED.code, sprints.code),
 				start_date = COALESCE(EXCLUDED.start_date, sprints.start_date),
 				end_date = COALESCE(EXCLUDED.end_date, sprints.end_date),
 				updated_at = NOW()
-		`, sprint.ID, sprint.Code, sprint.StartDate, sprint.EndDate)
-		if err != nil {
+			WHERE sprints.document_id = EXCLUDED.document_id
+		`, sprint.ID, sprint.Code, sprint.StartDate, sprint.EndDate, documentID)
+		if err := checkDocumentRow(result, err); err != nil {
 			return fmt.Errorf("failed to update sprint %s: %w", sprint.ID, err)
 		}
 	}
 
 	// Update resources
 	for _, resource := range req.Resources {
-		_, err := tx.Exec(`
-			INSERT INTO resources (id, team_ids, function, employee, fn_bg_color, fn_text_color, weeks, prev_id, next_id)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		result, err := tx.Exec(`
+			INSERT INTO resources (id, team_ids, function, employee, fn_bg_color, fn_text_color, weeks, prev_id, next_id, document_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 			ON CONFLICT (id) DO UPDATE SET
 				team_ids = COALESCE(EXCLUDED.team_ids, resources.team_ids),
 				function = COALESCE(EXCLUDED.function, resources.function),
@@ -587,6 +731,7 @@ func (r *Repository) UpdateData(tx *sql.Tx, req *models.UpdateRequest) error {
 				prev_id = COALESCE(EXCLUDED.prev_id, resources.prev_id),
 				next_id = COALESCE(EXCLUDED.next_id, resources.next_id),
 				updated_at = NOW()
+			WHERE resources.document_id = EXCLUDED.document_id
 		`, resource.ID,
 			func() interface{} {
 				if resource.TeamIDs != nil {
@@ -601,21 +746,21 @@ func (r *Repository) UpdateData(tx *sql.Tx, req *models.UpdateRequest) error {
 				}
 				return nil
 			}(),
-			resource.PrevID, resource.NextID)
-		if err != nil {
+			resource.PrevID, resource.NextID, documentID)
+		if err := checkDocumentRow(result, err); err != nil {
 			return fmt.Errorf("failed to update resource %s: %w", resource.ID, err)
 		}
 	}
 
 	// Update tasks
 	for _, task := range req.Tasks {
-		_, err := tx.Exec(`
+		result, err := tx.Exec(`
 			INSERT INTO tasks (
 				id, status, sprints_auto, epic, task_name, team_id, function, employee,
 				plan_empl, plan_weeks, blocker_ids, week_blockers, fact, start_week, end_week,
-				expected_start_week, auto_plan_enabled, weeks, prev_id, next_id
+				expected_start_week, auto_plan_enabled, weeks, prev_id, next_id, document_id
 			)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
 			ON CONFLICT (id) DO UPDATE SET
 				status = COALESCE(EXCLUDED.status, tasks.status),
 				sprints_auto = COALESCE(EXCLUDED.sprints_auto, tasks.sprints_auto),
@@ -637,6 +782,7 @@ func (r *Repository) UpdateData(tx *sql.Tx, req *models.UpdateRequest) error {
 				prev_id = COALESCE(EXCLUDED.prev_id, tasks.prev_id),
 				next_id = COALESCE(EXCLUDED.next_id, tasks.next_id),
 				updated_at = NOW()
+			WHERE tasks.document_id = EXCLUDED.document_id
 		`, task.ID, task.Status,
 			func() interface{} {
 				if task.SprintsAuto != nil {
@@ -666,8 +812,8 @@ func (r *Repository) UpdateData(tx *sql.Tx, req *models.UpdateRequest) error {
 				}
 				return nil
 			}(),
-			task.PrevID, task.NextID)
-		if err != nil {
+			task.PrevID, task.NextID, documentID)
+		if err := checkDocumentRow(result, err); err != nil {
 			return fmt.Errorf("failed to update task %s: %w", task.ID, err)
 		}
 	}
@@ -677,18 +823,12 @@ func (r *Repository) UpdateData(tx *sql.Tx, req *models.UpdateRequest) error {
 		for _, id := range ids {
 			var err error
 			switch tableName {
-			case "teams":
-				_, err = tx.Exec("DELETE FROM teams WHERE id = $1", id)
-			case "sprints":
-				_, err = tx.Exec("DELETE FROM sprints WHERE id = $1", id)
+			case "teams", "sprints", "resources", "tasks":
+				err = deleteDocumentRow(tx, tableName, id, documentID)
 			case "functions":
 				_, err = tx.Exec("DELETE FROM functions WHERE id = $1", id)
 			case "employees":
 				_, err = tx.Exec("DELETE FROM employees WHERE id = $1", id)
-			case "resources":
-				_, err = tx.Exec("DELETE FROM resources WHERE id = $1", id)
-			case "tasks":
-				_, err = tx.Exec("DELETE FROM tasks WHERE id = $1", id)
 			default:
 				return fmt.Errorf("unknown table for deletion: %s", tableName)
 			}
@@ -700,3 +840,99 @@ func (r *Repository) UpdateData(tx *sql.Tx, req *models.UpdateRequest) error {
 
 	return nil
 }
+
+// ClearTaskWeeks sets start and end weeks of the tasks to NULL within the
+// transaction of an update; a partial update can not write NULL
+func (r *Repository) ClearTaskWeeks(tx *sql.Tx, documentID uuid.UUID, ids []uuid.UUID) error {
+	_, err := tx.Exec(`
+		UPDATE tasks SET start_week = NULL, end_week = NULL, updated_at = NOW()
+		WHERE document_id = $1 AND id = ANY($2)
+	`, documentID, pq.Array(ids))
+	return err
+}
+
+// checkDocumentRow turns an upsert that matched a row of another document
+// (and therefore affected nothing) into an error
+func checkDocumentRow(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrOtherDocument
+	}
+	return nil
+}
+
+// deleteDocumentRow deletes a row of the document. Deleting a missing row is a
+// no-op, deleting a row of another document is an error.
+func deleteDocumentRow(tx *sql.Tx, table string, id, documentID uuid.UUID) error {
+	result, err := tx.Exec("DELETE FROM "+table+" WHERE id = $1 AND document_id = $2", id, documentID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		return nil
+	}
+	var foreign bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM "+table+" WHERE id = $1)", id).Scan(&foreign)
+	if err != nil {
+		return err
+	}
+	if foreign {
+		return ErrOtherDocument
+	}
+	return nil
+}
+
+// checkReferences returns ErrOtherDocument when the request links teams,
+// resources or tasks of another document: team ids, blockers and list
+// neighbours. The foreign keys only check that the rows exist.
+func checkReferences(tx *sql.Tx, documentID uuid.UUID, req *models.UpdateRequest) error {
+	refs := map[string][]string{}
+	addID := func(table string, id *uuid.UUID) {
+		if id != nil {
+			refs[table] = append(refs[table], id.String())
+		}
+	}
+	for _, resource := range req.Resources {
+		if resource.TeamIDs != nil {
+			refs["teams"] = append(refs["teams"], *resource.TeamIDs...)
+		}
+		addID("resources", resource.PrevID)
+		addID("resources", resource.NextID)
+	}
+	for _, task := range req.Tasks {
+		addID("teams", task.TeamID)
+		if task.BlockerIDs != nil {
+			refs["tasks"] = append(refs["tasks"], *task.BlockerIDs...)
+		}
+		addID("tasks", task.PrevID)
+		addID("tasks", task.NextID)
+	}
+
+	for _, table := range []string{"teams", "resources", "tasks"} {
+		if len(refs[table]) == 0 {
+			continue
+		}
+		var foreign sql.NullString
+		err := tx.QueryRow(
+			"SELECT MIN(id::text) FROM "+table+" WHERE id = ANY($1) AND document_id <> $2",
+			pq.Array(refs[table]), documentID,
+		).Scan(&foreign)
+		if err != nil {
+			return fmt.Errorf("failed to check %s references: %w", table, err)
+		}
+		if foreign.Valid {
+			return fmt.Errorf("%s %s: %w", table, foreign.String, ErrOtherDocument)
+		}
+	}
+	return nil
+}
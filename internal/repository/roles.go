@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"roadmap/internal/models"
+)
+
+const roleGrantColumns = `id, user_id, role, team_id, created_by, created_at`
+
+// GetRoleGrants returns the role grants of the document, optionally of one user only
+func (r *Repository) GetRoleGrants(documentID uuid.UUID, userID string) ([]models.RoleGrant, error) {
+	rows, err := r.db.Query(`
+		SELECT `+roleGrantColumns+`
+		FROM user_roles
+		WHERE document_id = $1 AND ($2 = '' OR user_id = $2)
+		ORDER BY user_id, team_id NULLS FIRST
+	`, documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	grants := []models.RoleGrant{}
+	for rows.Next() {
+		grant, err := scanRoleGrant(rows)
+		if err != nil {
+			return nil, err
+		}
+		grants = append(grants, *grant)
+	}
+	return grants, rows.Err()
+}
+
+// GrantRole sets the role of the user for the team (or the whole document when
+// the team is nil), replacing the previous one. It returns sql.ErrNoRows when
+// the team does not belong to the document.
+func (r *Repository) GrantRole(documentID uuid.UUID, req *models.GrantRoleRequest, createdBy string) (*models.RoleGrant, error) {
+	row := r.db.QueryRow(`
+		INSERT INTO user_roles (document_id, user_id, role, team_id, created_by)
+		SELECT $1, $2, $3, $4, NULLIF($5, '')
+		WHERE $4::uuid IS NULL OR EXISTS (SELECT 1 FROM teams WHERE id = $4 AND document_id = $1)
+		ON CONFLICT (document_id, user_id, COALESCE(team_id, '00000000-0000-0000-0000-000000000000'::uuid))
+		DO UPDATE SET role = EXCLUDED.role, created_by = EXCLUDED.created_by, created_at = NOW()
+		RETURNING `+roleGrantColumns,
+		documentID, req.UserID, req.Role, req.TeamID, createdBy)
+	return scanRoleGrant(row)
+}
+
+// RevokeRole removes a role grant. It returns sql.ErrNoRows when the grant does
+// not exist.
+func (r *Repository) RevokeRole(documentID, id uuid.UUID) error {
+	result, err := r.db.Exec("DELETE FROM user_roles WHERE document_id = $1 AND id = $2", documentID, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetTaskTeams returns the current team of each existing task; tasks without
+// a team map to nil
+func (r *Repository) GetTaskTeams(documentID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID]*uuid.UUID, error) {
+	teams := make(map[uuid.UUID]*uuid.UUID, len(ids))
+	if len(ids) == 0 {
+		return teams, nil
+	}
+
+	rows, err := r.db.Query(
+		"SELECT id, team_id FROM tasks WHERE document_id = $1 AND id = ANY($2)", documentID, pq.Array(ids),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var teamID uuid.NullUUID
+		if err := rows.Scan(&id, &teamID); err != nil {
+			return nil, err
+		}
+		teams[id] = nil
+		if teamID.Valid {
+			teams[id] = &teamID.UUID
+		}
+	}
+	return teams, rows.Err()
+}
+
+// GetResourceTeams returns the current team ids of each existing resource
+func (r *Repository) GetResourceTeams(documentID uuid.UUID, ids []uuid.UUID) (map[uuid.UUID][]string, error) {
+	teams := make(map[uuid.UUID][]string, len(ids))
+	if len(ids) == 0 {
+		return teams, nil
+	}
+
+	rows, err := r.db.Query(
+		"SELECT id, team_ids FROM resources WHERE document_id = $1 AND id = ANY($2)", documentID, pq.Array(ids),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var teamIDs pq.StringArray
+		if err := rows.Scan(&id, &teamIDs); err != nil {
+			return nil, err
+		}
+		teams[id] = teamIDs
+	}
+	return teams, rows.Err()
+}
+
+func scanRoleGrant(row rowScanner) (*models.RoleGrant, error) {
+	var grant models.RoleGrant
+	var teamID uuid.NullUUID
+	var createdBy sql.NullString
+
+	err := row.Scan(&grant.ID, &grant.UserID, &grant.Role, &teamID, &createdBy, &grant.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if teamID.Valid {
+		grant.TeamID = &teamID.UUID
+	}
+	if createdBy.Valid {
+		grant.CreatedBy = &createdBy.String
+	}
+	return &grant, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+)
+
+const scenarioColumns = `id, name, base_version, version, status, changes, created_by, created_at, updated_at`
+
+// GetScenarios returns all scenarios of the document
+func (r *Repository) GetScenarios(documentID uuid.UUID) ([]models.Scenario, error) {
+	rows, err := r.db.Query(`
+		SELECT `+scenarioColumns+`
+		FROM scenarios
+		WHERE document_id = $1
+		ORDER BY created_at
+	`, documentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scenarios []models.Scenario
+	for rows.Next() {
+		scenario, err := scanScenario(rows)
+		if err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, *scenario)
+	}
+
+	return scenarios, rows.Err()
+}
+
+// GetScenario returns a scenario of the document
+func (r *Repository) GetScenario(documentID, id uuid.UUID) (*models.Scenario, error) {
+	row := r.db.QueryRow(`
+		SELECT `+scenarioColumns+`
+		FROM scenarios
+		WHERE document_id = $1 AND id = $2
+	`, documentID, id)
+	return scanScenario(row)
+}
+
+// CreateScenario forks the document at the given version into an empty scenario
+func (r *Repository) CreateScenario(documentID uuid.UUID, name string, baseVersion int64, createdBy string) (*models.Scenario, error) {
+	row := r.db.QueryRow(`
+		INSERT INTO scenarios (document_id, name, base_version, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+scenarioColumns,
+		documentID, name, baseVersion, createdBy)
+	return scanScenario(row)
+}
+
+// UpdateScenarioChanges stores new overrides if the scenario is still open and
+// at the expected version and returns the incremented version. It returns
+// sql.ErrNoRows when the version does not match or the scenario was closed.
+func (r *Repository) UpdateScenarioChanges(id uuid.UUID, expectedVersion int64, changes *models.UpdateRequest) (int64, error) {
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal scenario changes: %w", err)
+	}
+
+	var version int64
+	err = r.db.QueryRow(`
+		UPDATE scenarios
+		SET changes = $3, version = version + 1
+		WHERE id = $1 AND version = $2 AND status = $4
+		RETURNING version
+	`, id, expectedVersion, string(data), models.ScenarioStatusOpen).Scan(&version)
+	return version, err
+}
+
+// SetScenarioStatus closes an open scenario with the status. It returns
+// sql.ErrNoRows when the scenario is not open.
+func (r *Repository) SetScenarioStatus(id uuid.UUID, status models.ScenarioStatus) error {
+	return setScenarioStatus(r.db, id, status)
+}
+
+// MarkScenarioMerged closes an open scenario as merged within the transaction
+// that applies it to the main plan. It returns sql.ErrNoRows when the
+// scenario is not open.
+func (r *Repository) MarkScenarioMerged(tx *sql.Tx, id uuid.UUID) error {
+	return setScenarioStatus(tx, id, models.ScenarioStatusMerged)
+}
+
+func setScenarioStatus(db queryer, id uuid.UUID, status models.ScenarioStatus) error {
+	var closed uuid.UUID
+	return db.QueryRow(`
+		UPDATE scenarios SET status = $2
+		WHERE id = $1 AND status = $3
+		RETURNING id
+	`, id, status, models.ScenarioStatusOpen).Scan(&closed)
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanScenario(row rowScanner) (*models.Scenario, error) {
+	var scenario models.Scenario
+	var status string
+	var changes []byte
+	var createdBy sql.NullString
+
+	err := row.Scan(
+		&scenario.ID, &scenario.Name, &scenario.BaseVersion, &scenario.Version, &status,
+		&changes, &createdBy, &scenario.CreatedAt, &scenario.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	scenario.Status = models.ScenarioStatus(status)
+	if createdBy.Valid {
+		scenario.CreatedBy = &createdBy.String
+	}
+	if err := json.Unmarshal(changes, &scenario.Changes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scenario changes: %w", err)
+	}
+
+	return &scenario, nil
+}
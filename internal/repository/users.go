@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"roadmap/internal/models"
+)
+
+const userColumns = `id, display_name, email, avatar_color, created_at, updated_at`
+
+// GetUsers returns registered users ordered by display name
+func (r *Repository) GetUsers() ([]models.User, error) {
+	rows, err := r.db.Query(`SELECT ` + userColumns + ` FROM users ORDER BY display_name, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+	return users, rows.Err()
+}
+
+// GetUser returns a registered user or sql.ErrNoRows
+func (r *Repository) GetUser(id string) (*models.User, error) {
+	return scanUser(r.db.QueryRow(`SELECT `+userColumns+` FROM users WHERE id = $1`, id))
+}
+
+// GetUserDirectory returns the registered users among the ids
+func (r *Repository) GetUserDirectory(ids []string) (models.UserDirectory, error) {
+	directory := make(models.UserDirectory, len(ids))
+	if len(ids) == 0 {
+		return directory, nil
+	}
+
+	rows, err := r.db.Query(`SELECT `+userColumns+` FROM users WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		directory[user.ID] = *user
+	}
+	return directory, rows.Err()
+}
+
+// UpsertUser registers the user or updates its display info
+func (r *Repository) UpsertUser(user *models.User) (*models.User, error) {
+	row := r.db.QueryRow(`
+		INSERT INTO users (id, display_name, email, avatar_color)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET
+			display_name = EXCLUDED.display_name,
+			email = EXCLUDED.email,
+			avatar_color = EXCLUDED.avatar_color,
+			updated_at = NOW()
+		RETURNING `+userColumns,
+		user.ID, user.DisplayName, user.Email, user.AvatarColor)
+	return scanUser(row)
+}
+
+func scanUser(row rowScanner) (*models.User, error) {
+	var user models.User
+	var email sql.NullString
+
+	err := row.Scan(&user.ID, &user.DisplayName, &email, &user.AvatarColor, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if email.Valid {
+		user.Email = &email.String
+	}
+	return &user, nil
+}
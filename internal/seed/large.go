@@ -0,0 +1,114 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"roadmap/internal/models"
+)
+
+// largeNamespace derives the ids of generated rows from their position
+var largeNamespace = uuid.NewSHA1(uuid.NameSpaceOID, []byte("roadmap/seed/large"))
+
+// largeFunctions are the functions every generated team has, with the
+// employees of each
+var largeFunctions = []struct {
+	name      string
+	employees int
+}{
+	{"BE", 3},
+	{"FE", 2},
+	{"QA", 1},
+}
+
+const (
+	largeSprints     = 26
+	largeSprintStart = "2026-01-12" // A Monday
+)
+
+// Generate returns a synthetic roadmap with the given number of teams and
+// tasks for performance tests. The same arguments always produce the same data.
+func Generate(teams, tasks int, randomSeed int64) (*Fixture, error) {
+	if teams < 1 {
+		return nil, fmt.Errorf("teams must be at least 1, got %d", teams)
+	}
+	if tasks < 0 {
+		return nil, fmt.Errorf("tasks must not be negative, got %d", tasks)
+	}
+
+	random := rand.New(rand.NewSource(randomSeed))
+	id := func(kind string, n int) uuid.UUID {
+		return uuid.NewSHA1(largeNamespace, []byte(fmt.Sprintf("%s/%d", kind, n)))
+	}
+	fixture := &Fixture{}
+
+	start, _ := time.Parse(time.DateOnly, largeSprintStart)
+	for i := 0; i < largeSprints; i++ {
+		sprintStart := start.AddDate(0, 0, 14*i)
+		code := fmt.Sprintf("Q%dS%d", i/6+1, i%6+1)
+		startDate := sprintStart.Format(time.DateOnly)
+		endDate := sprintStart.AddDate(0, 0, 11).Format(time.DateOnly)
+		fixture.Sprints = append(fixture.Sprints, models.Sprint{
+			ID: id("sprint", i), Code: &code, StartDate: &startDate, EndDate: &endDate,
+		})
+	}
+
+	weeks := 2 * largeSprints
+	for t := 0; t < teams; t++ {
+		name := fmt.Sprintf("Team %03d", t+1)
+		project := fmt.Sprintf("T%03d", t+1)
+		fixture.Teams = append(fixture.Teams, models.Team{ID: id("team", t), Name: &name, JiraProject: &project})
+
+		teamIDs := pq.StringArray{id("team", t).String()}
+		for _, function := range largeFunctions {
+			for e := 0; e < function.employees; e++ {
+				fn := function.name
+				employee := fmt.Sprintf("%s %s-%d", name, function.name, e+1)
+				capacity := make(pq.Float64Array, weeks)
+				for w := range capacity {
+					capacity[w] = 1
+					if random.Intn(20) == 0 { // Vacation weeks
+						capacity[w] = 0
+					}
+				}
+				fixture.Resources = append(fixture.Resources, models.ResourceUpdate{
+					ID: id("resource", len(fixture.Resources)), TeamIDs: &teamIDs,
+					Function: &fn, Employee: &employee, Weeks: &capacity,
+				})
+			}
+		}
+	}
+
+	statuses := []models.TaskStatus{models.TaskStatusTodo, models.TaskStatusTodo, models.TaskStatusTodo, models.TaskStatusBacklog}
+	teamTasks := make([][]uuid.UUID, teams)
+	for i := 0; i < tasks; i++ {
+		t := i % teams
+		taskID := id("task", i)
+		teamID := id("team", t)
+		status := statuses[random.Intn(len(statuses))]
+		epic := fmt.Sprintf("Epic %d", i/(teams*10)+1)
+		name := fmt.Sprintf("Task %d", i+1)
+		fn := largeFunctions[random.Intn(len(largeFunctions))].name
+		planEmpl := float64(1 + random.Intn(2))
+		planWeeks := float64(1 + random.Intn(4))
+		autoPlan := true
+
+		// Every fifth task waits for a recent task of its team
+		blockers := pq.StringArray{}
+		if previous := teamTasks[t]; len(previous) > 0 && random.Intn(5) == 0 {
+			window := min(len(previous), 10)
+			blockers = append(blockers, previous[len(previous)-1-random.Intn(window)].String())
+		}
+		teamTasks[t] = append(teamTasks[t], taskID)
+
+		fixture.Tasks = append(fixture.Tasks, models.TaskUpdate{
+			ID: taskID, Status: &status, Epic: &epic, TaskName: &name, TeamID: &teamID, Function: &fn,
+			PlanEmpl: &planEmpl, PlanWeeks: &planWeeks, BlockerIDs: &blockers, AutoPlanEnabled: &autoPlan,
+		})
+	}
+	return fixture, nil
+}
@@ -0,0 +1,272 @@
+// Package seed loads named fixture sets into a roadmap document through the
+// regular update path, so seeded data gets versions and change_log entries
+// like any edit
+package seed
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"roadmap/internal/models"
+	"roadmap/internal/service"
+)
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// Large is the name of the generated fixture set
+const Large = "large"
+
+// batchSize limits the rows written per change set
+const batchSize = 1000
+
+// UserID is the author of seeded changes
+var UserID = uuid.NewSHA1(uuid.NameSpaceOID, []byte("roadmap/seed")).String()
+
+// ErrNotEmpty is returned when seeding a roadmap that already has data without reset
+var ErrNotEmpty = errors.New("roadmap already has data")
+
+// Fixture is the content of a fixture set in the format of PUT /data.
+// Row order is the display order; prevId and nextId are ignored.
+type Fixture struct {
+	Teams     []models.Team           `json:"teams"`
+	Sprints   []models.Sprint         `json:"sprints"`
+	Resources []models.ResourceUpdate `json:"resources"`
+	Tasks     []models.TaskUpdate     `json:"tasks"`
+}
+
+// Names returns the available fixture sets
+func Names() []string {
+	names := []string{Large}
+	files, _ := fs.Glob(fixtures, "fixtures/*.json")
+	for _, file := range files {
+		names = append(names, strings.TrimSuffix(path.Base(file), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load returns an embedded fixture set
+func Load(name string) (*Fixture, error) {
+	content, err := fixtures.ReadFile("fixtures/" + name + ".json")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("unknown fixture set %q, expected one of %s", name, strings.Join(Names(), ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(content, &fixture); err != nil {
+		return nil, fmt.Errorf("fixture set %s: %w", name, err)
+	}
+	return &fixture, nil
+}
+
+// Result summarizes a seeded roadmap
+type Result struct {
+	Deleted   int
+	Teams     int
+	Sprints   int
+	Resources int
+	Tasks     int
+	Version   int64
+}
+
+// Apply writes the fixture to the document as the user. With reset existing
+// rows are deleted first, otherwise the document must be empty. Ids of the fixture are
+// mapped to ids scoped to the document, so the same set can seed several
+// roadmaps and reseeding a roadmap reproduces the same ids.
+func Apply(svc *service.Service, documentID uuid.UUID, fixture *Fixture, reset bool, userID string) (*Result, error) {
+	data, err := svc.GetAllData(documentID)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Version: data.Version}
+
+	existing := len(data.Teams) + len(data.Sprints) + len(data.Resources) + len(data.Tasks)
+	if existing > 0 && !reset {
+		return nil, fmt.Errorf("%d rows: %w", existing, ErrNotEmpty)
+	}
+
+	// Rows referencing teams go first
+	deletions := []struct {
+		table string
+		ids   []uuid.UUID
+	}{
+		{"tasks", taskIDs(data.Tasks)},
+		{"resources", resourceIDs(data.Resources)},
+		{"sprints", sprintIDs(data.Sprints)},
+		{"teams", teamIDs(data.Teams)},
+	}
+	for _, deletion := range deletions {
+		for start := 0; start < len(deletion.ids); start += batchSize {
+			ids := deletion.ids[start:min(start+batchSize, len(deletion.ids))]
+			req := &models.UpdateRequest{Deleted: map[string][]uuid.UUID{deletion.table: ids}}
+			if err := update(svc, documentID, userID, result, req); err != nil {
+				return nil, fmt.Errorf("failed to delete %s: %w", deletion.table, err)
+			}
+			result.Deleted += len(ids)
+		}
+	}
+
+	fixture = fixture.scoped(documentID)
+
+	if len(fixture.Teams) > 0 || len(fixture.Sprints) > 0 {
+		req := &models.UpdateRequest{Teams: fixture.Teams, Sprints: fixture.Sprints}
+		if err := update(svc, documentID, userID, result, req); err != nil {
+			return nil, fmt.Errorf("failed to seed teams and sprints: %w", err)
+		}
+		result.Teams, result.Sprints = len(fixture.Teams), len(fixture.Sprints)
+	}
+
+	// Rows are linked to the previous one on insert and the previous one to
+	// them afterwards, as the foreign keys require the neighbour to exist
+	for start := 0; start < len(fixture.Resources); start += batchSize {
+		end := min(start+batchSize, len(fixture.Resources))
+		req := &models.UpdateRequest{}
+		for i := start; i < end; i++ {
+			resource := fixture.Resources[i]
+			resource.PrevID, resource.NextID = nil, nil
+			if i > 0 {
+				resource.PrevID = &fixture.Resources[i-1].ID
+			}
+			req.Resources = append(req.Resources, resource)
+		}
+		for i := max(start, 1); i < end; i++ {
+			req.Resources = append(req.Resources, models.ResourceUpdate{ID: fixture.Resources[i-1].ID, NextID: &fixture.Resources[i].ID})
+		}
+		if err := update(svc, documentID, userID, result, req); err != nil {
+			return nil, fmt.Errorf("failed to seed resources: %w", err)
+		}
+		result.Resources = end
+	}
+
+	for start := 0; start < len(fixture.Tasks); start += batchSize {
+		end := min(start+batchSize, len(fixture.Tasks))
+		req := &models.UpdateRequest{}
+		for i := start; i < end; i++ {
+			task := fixture.Tasks[i]
+			task.PrevID, task.NextID = nil, nil
+			if i > 0 {
+				task.PrevID = &fixture.Tasks[i-1].ID
+			}
+			req.Tasks = append(req.Tasks, task)
+		}
+		for i := max(start, 1); i < end; i++ {
+			req.Tasks = append(req.Tasks, models.TaskUpdate{ID: fixture.Tasks[i-1].ID, NextID: &fixture.Tasks[i].ID})
+		}
+		if err := update(svc, documentID, userID, result, req); err != nil {
+			return nil, fmt.Errorf("failed to seed tasks: %w", err)
+		}
+		result.Tasks = end
+	}
+
+	return result, nil
+}
+
+// update applies one change set at the current version
+func update(svc *service.Service, documentID uuid.UUID, userID string, result *Result, req *models.UpdateRequest) error {
+	req.Version = result.Version
+	req.UserID = userID
+	response, err := svc.UpdateData(documentID, req)
+	if err != nil {
+		return err
+	}
+	if !response.Success {
+		return errors.New(response.Error)
+	}
+	result.Version = response.Version
+	return nil
+}
+
+// scoped returns a copy of the fixture with ids derived from the document
+func (f *Fixture) scoped(documentID uuid.UUID) *Fixture {
+	scope := func(id uuid.UUID) uuid.UUID {
+		return uuid.NewSHA1(documentID, id[:])
+	}
+	scopeStrings := func(values *pq.StringArray) *pq.StringArray {
+		if values == nil {
+			return nil
+		}
+		scoped := make(pq.StringArray, len(*values))
+		for i, value := range *values {
+			scoped[i] = value
+			if id, err := uuid.Parse(value); err == nil {
+				scoped[i] = scope(id).String()
+			}
+		}
+		return &scoped
+	}
+
+	scoped := &Fixture{
+		Teams:     make([]models.Team, len(f.Teams)),
+		Sprints:   make([]models.Sprint, len(f.Sprints)),
+		Resources: make([]models.ResourceUpdate, len(f.Resources)),
+		Tasks:     make([]models.TaskUpdate, len(f.Tasks)),
+	}
+	for i, team := range f.Teams {
+		team.ID = scope(team.ID)
+		scoped.Teams[i] = team
+	}
+	for i, sprint := range f.Sprints {
+		sprint.ID = scope(sprint.ID)
+		scoped.Sprints[i] = sprint
+	}
+	for i, resource := range f.Resources {
+		resource.ID = scope(resource.ID)
+		resource.TeamIDs = scopeStrings(resource.TeamIDs)
+		scoped.Resources[i] = resource
+	}
+	for i, task := range f.Tasks {
+		task.ID = scope(task.ID)
+		if task.TeamID != nil {
+			teamID := scope(*task.TeamID)
+			task.TeamID = &teamID
+		}
+		task.BlockerIDs = scopeStrings(task.BlockerIDs)
+		scoped.Tasks[i] = task
+	}
+	return scoped
+}
+
+func teamIDs(teams []models.Team) []uuid.UUID {
+	ids := make([]uuid.UUID, len(teams))
+	for i := range teams {
+		ids[i] = teams[i].ID
+	}
+	return ids
+}
+
+func sprintIDs(sprints []models.Sprint) []uuid.UUID {
+	ids := make([]uuid.UUID, len(sprints))
+	for i := range sprints {
+		ids[i] = sprints[i].ID
+	}
+	return ids
+}
+
+func resourceIDs(resources []models.Resource) []uuid.UUID {
+	ids := make([]uuid.UUID, len(resources))
+	for i := range resources {
+		ids[i] = resources[i].ID
+	}
+	return ids
+}
+
+func taskIDs(tasks []models.Task) []uuid.UUID {
+	ids := make([]uuid.UUID, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+	return ids
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+)
+
+// ErrForbidden is returned when the user lacks the role for an operation
+var ErrForbidden = errors.New("forbidden")
+
+// ErrInvalidRole is returned for unknown roles
+var ErrInvalidRole = errors.New("invalid role")
+
+// roleRank orders roles; each role includes the permissions of lower ones
+var roleRank = map[models.Role]int{
+	models.RoleViewer:   1,
+	models.RolePlanner:  2,
+	models.RoleTeamLead: 3,
+	models.RoleAdmin:    4,
+}
+
+// EnableAccessControl makes reads and every change check the roles of the
+// user. The listed users are admins of every roadmap. Without it every user
+// may read and change everything.
+func (s *Service) EnableAccessControl(admins []string) {
+	s.accessControl = true
+	s.admins = make(map[string]bool, len(admins))
+	for _, admin := range admins {
+		if id, err := uuid.Parse(admin); err == nil {
+			admin = id.String()
+		}
+		s.admins[admin] = true
+	}
+}
+
+// GetRoleGrants returns all role grants of the document
+func (s *Service) GetRoleGrants(documentID uuid.UUID) ([]models.RoleGrant, error) {
+	grants, err := s.repo.GetRoleGrants(documentID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles: %w", err)
+	}
+	return grants, nil
+}
+
+// GrantRole sets the role of a user for a team or the whole document; only
+// roadmap-wide admins may manage roles
+func (s *Service) GrantRole(documentID uuid.UUID, actorID string, req *models.GrantRoleRequest) (*models.RoleGrant, error) {
+	if _, ok := roleRank[req.Role]; !ok {
+		return nil, fmt.Errorf("role %q: %w", req.Role, ErrInvalidRole)
+	}
+	if err := s.requireRole(documentID, actorID, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	grant, err := s.repo.GrantRole(documentID, req, actorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("team %s: %w", req.TeamID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to grant role: %w", err)
+	}
+	return grant, nil
+}
+
+// RevokeRole removes a role grant; only roadmap-wide admins may manage roles
+func (s *Service) RevokeRole(documentID uuid.UUID, actorID string, id uuid.UUID) error {
+	if err := s.requireRole(documentID, actorID, models.RoleAdmin); err != nil {
+		return err
+	}
+
+	err := s.repo.RevokeRole(documentID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("role grant %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke role: %w", err)
+	}
+	return nil
+}
+
+// RequireReadAccess checks that the user has at least the viewer role for
+// some team of the document
+func (s *Service) RequireReadAccess(documentID uuid.UUID, userID string) error {
+	if !s.accessControl {
+		return nil
+	}
+	perms, err := s.permissions(documentID, userID)
+	if err != nil {
+		return err
+	}
+	if !perms.allowsAnywhere(models.RoleViewer) {
+		return fmt.Errorf("reading the roadmap requires the %s role: %w", models.RoleViewer, ErrForbidden)
+	}
+	return nil
+}
+
+// requireRole checks that the user has the role for the whole document
+func (s *Service) requireRole(documentID uuid.UUID, userID string, role models.Role) error {
+	if !s.accessControl {
+		return nil
+	}
+	perms, err := s.permissions(documentID, userID)
+	if err != nil {
+		return err
+	}
+	if !perms.allows(role, nil) {
+		return fmt.Errorf("the operation requires the roadmap-wide %s role: %w", role, ErrForbidden)
+	}
+	return nil
+}
+
+// permissions holds the roles of a user in a document
+type permissions struct {
+	all   models.Role               // Role for every team
+	teams map[uuid.UUID]models.Role // Roles for single teams
+}
+
+func (s *Service) permissions(documentID uuid.UUID, userID string) (*permissions, error) {
+	perms := &permissions{teams: make(map[uuid.UUID]models.Role)}
+	if s.admins[userID] {
+		perms.all = models.RoleAdmin
+		return perms, nil
+	}
+
+	grants, err := s.repo.GetRoleGrants(documentID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles: %w", err)
+	}
+	for _, grant := range grants {
+		if grant.TeamID == nil {
+			perms.all = grant.Role
+		} else {
+			perms.teams[*grant.TeamID] = grant.Role
+		}
+	}
+	return perms, nil
+}
+
+// allows reports whether the user has the role for every one of the teams.
+// Records without a team need the role for all teams.
+func (p *permissions) allows(role models.Role, teams []uuid.UUID) bool {
+	if roleRank[p.all] >= roleRank[role] {
+		return true
+	}
+	if len(teams) == 0 {
+		return false
+	}
+	for _, team := range teams {
+		if roleRank[p.teams[team]] < roleRank[role] {
+			return false
+		}
+	}
+	return true
+}
+
+// allowsAnywhere reports whether the user has the role for at least one team
+func (p *permissions) allowsAnywhere(role models.Role) bool {
+	if roleRank[p.all] >= roleRank[role] {
+		return true
+	}
+	for _, granted := range p.teams {
+		if roleRank[granted] >= roleRank[role] {
+			return true
+		}
+	}
+	return false
+}
+
+// forbiddenChanges checks every item of the update against the roles of the
+// user for the teams it belongs to before and after the change:
+//   - tasks need planner, resources need teamLead;
+//   - updates that only move a row (prevId/nextId) need the role for any team,
+//     as neighbours of a moved row may belong to other teams;
+//   - sprints and new teams need admin for all teams, teams need admin for the team;
+//   - deletions need admin.
+func (s *Service) forbiddenChanges(documentID uuid.UUID, req *models.UpdateRequest) ([]models.ForbiddenItem, error) {
+	perms, err := s.permissions(documentID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var taskIDs, resourceIDs []uuid.UUID
+	for _, task := range req.Tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+	for _, resource := range req.Resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	taskIDs = append(taskIDs, req.Deleted["tasks"]...)
+	resourceIDs = append(resourceIDs, req.Deleted["resources"]...)
+
+	taskTeams, err := s.repo.GetTaskTeams(documentID, taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task teams: %w", err)
+	}
+	resourceTeams, err := s.repo.GetResourceTeams(documentID, resourceIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource teams: %w", err)
+	}
+
+	var forbidden []models.ForbiddenItem
+	deny := func(table string, id uuid.UUID, action string, role models.Role) {
+		forbidden = append(forbidden, models.ForbiddenItem{Table: table, ID: id, Action: action, RequiredRole: role})
+	}
+
+	for _, team := range req.Teams {
+		if !perms.allows(models.RoleAdmin, []uuid.UUID{team.ID}) {
+			deny("teams", team.ID, "update", models.RoleAdmin)
+		}
+	}
+	for _, sprint := range req.Sprints {
+		if !perms.allows(models.RoleAdmin, nil) {
+			deny("sprints", sprint.ID, "update", models.RoleAdmin)
+		}
+	}
+	for _, resource := range req.Resources {
+		if isResourceMove(resource) {
+			if !perms.allowsAnywhere(models.RoleTeamLead) {
+				deny("resources", resource.ID, "update", models.RoleTeamLead)
+			}
+			continue
+		}
+		teams := parseTeamIDs(resourceTeams[resource.ID])
+		if resource.TeamIDs != nil {
+			teams = append(teams, parseTeamIDs(*resource.TeamIDs)...)
+		}
+		if !perms.allows(models.RoleTeamLead, teams) {
+			deny("resources", resource.ID, "update", models.RoleTeamLead)
+		}
+	}
+	for _, task := range req.Tasks {
+		if isTaskMove(task) {
+			if !perms.allowsAnywhere(models.RolePlanner) {
+				deny("tasks", task.ID, "update", models.RolePlanner)
+			}
+			continue
+		}
+		var teams []uuid.UUID
+		if team := taskTeams[task.ID]; team != nil {
+			teams = append(teams, *team)
+		}
+		if task.TeamID != nil {
+			teams = append(teams, *task.TeamID)
+		}
+		if !perms.allows(models.RolePlanner, teams) {
+			deny("tasks", task.ID, "update", models.RolePlanner)
+		}
+	}
+
+	tables := make([]string, 0, len(req.Deleted))
+	for table := range req.Deleted {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		for _, id := range req.Deleted[table] {
+			var teams []uuid.UUID
+			switch table {
+			case "tasks":
+				if team := taskTeams[id]; team != nil {
+					teams = append(teams, *team)
+				}
+			case "resources":
+				teams = parseTeamIDs(resourceTeams[id])
+			case "teams":
+				teams = append(teams, id)
+			}
+			if !perms.allows(models.RoleAdmin, teams) {
+				deny(table, id, "delete", models.RoleAdmin)
+			}
+		}
+	}
+
+	return forbidden, nil
+}
+
+// isTaskMove reports whether the update only changes the position of the task
+func isTaskMove(task models.TaskUpdate) bool {
+	return (task.PrevID != nil || task.NextID != nil) &&
+		task == models.TaskUpdate{ID: task.ID, PrevID: task.PrevID, NextID: task.NextID}
+}
+
+// isResourceMove reports whether the update only changes the position of the resource
+func isResourceMove(resource models.ResourceUpdate) bool {
+	return (resource.PrevID != nil || resource.NextID != nil) &&
+		resource == models.ResourceUpdate{ID: resource.ID, PrevID: resource.PrevID, NextID: resource.NextID}
+}
+
+// parseTeamIDs converts team_ids values to UUIDs, skipping malformed ones
+func parseTeamIDs(values []string) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, value := range values {
+		if id, err := uuid.Parse(value); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
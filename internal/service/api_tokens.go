@@ -0,0 +1,161 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+)
+
+// ErrInvalidToken is returned for API token requests that fail validation
+var ErrInvalidToken = errors.New("invalid API token request")
+
+// GetAPITokens returns the API tokens of the document; only roadmap-wide admins
+// may manage tokens
+func (s *Service) GetAPITokens(documentID uuid.UUID, actorID string) ([]models.APIToken, error) {
+	if err := s.requireRole(documentID, actorID, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.repo.GetAPITokens(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// CreateAPIToken creates a token with its own service user; the secret is only
+// returned here, the database keeps its hash
+func (s *Service) CreateAPIToken(documentID uuid.UUID, actorID string, req *models.CreateAPITokenRequest) (*models.CreateAPITokenResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 200 {
+		return nil, fmt.Errorf("name must be 1 to 200 characters: %w", ErrInvalidToken)
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("expiresAt must be in the future: %w", ErrInvalidToken)
+	}
+
+	token := &models.APIToken{
+		DocumentID:    documentID,
+		Name:          name,
+		Scope:         req.Scope,
+		ServiceUserID: uuid.New().String(),
+		ExpiresAt:     req.ExpiresAt,
+	}
+	switch req.Scope {
+	case models.APITokenRead:
+		if req.Role != nil {
+			return nil, fmt.Errorf("read tokens have no role: %w", ErrInvalidToken)
+		}
+		// Reads are not filtered by team, so a team limit would not hold
+		if len(req.TeamIDs) > 0 {
+			return nil, fmt.Errorf("read tokens cover the whole roadmap and take no teamIds: %w", ErrInvalidToken)
+		}
+	case models.APITokenWrite:
+		role := models.RolePlanner
+		if req.Role != nil {
+			role = *req.Role
+		}
+		if roleRank[role] <= roleRank[models.RoleViewer] {
+			return nil, fmt.Errorf("role %q: %w", role, ErrInvalidRole)
+		}
+		token.Role = &role
+	default:
+		return nil, fmt.Errorf("scope must be %q or %q: %w", models.APITokenRead, models.APITokenWrite, ErrInvalidToken)
+	}
+	seen := make(map[uuid.UUID]bool)
+	for _, teamID := range req.TeamIDs {
+		if !seen[teamID] {
+			seen[teamID] = true
+			token.TeamIDs = append(token.TeamIDs, teamID)
+		}
+	}
+	if actorID != "" {
+		token.CreatedBy = &actorID
+	}
+
+	if err := s.requireRole(documentID, actorID, models.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	secret, err := newAPITokenSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API token: %w", err)
+	}
+	token.Prefix = secret[:len(models.APITokenPrefix)+6]
+
+	serviceUser := &models.User{
+		ID:          token.ServiceUserID,
+		DisplayName: name + " (API token)",
+		AvatarColor: defaultAvatarColor(token.ServiceUserID),
+	}
+	created, err := s.repo.CreateAPIToken(token, hashAPIToken(secret), serviceUser)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("teams %v: %w", token.TeamIDs, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API token: %w", err)
+	}
+
+	return &models.CreateAPITokenResponse{APIToken: *created, Token: secret}, nil
+}
+
+// RevokeAPIToken revokes a token and the roles of its service user
+func (s *Service) RevokeAPIToken(documentID uuid.UUID, actorID string, id uuid.UUID) error {
+	if err := s.requireRole(documentID, actorID, models.RoleAdmin); err != nil {
+		return err
+	}
+
+	err := s.repo.RevokeAPIToken(documentID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("API token %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke API token %s: %w", id, err)
+	}
+	return nil
+}
+
+// AuthenticateAPIToken returns the active token with the secret, or nil when
+// it is unknown, revoked or expired, and records its use
+func (s *Service) AuthenticateAPIToken(secret string) (*models.APIToken, error) {
+	token, err := s.repo.GetActiveAPIToken(hashAPIToken(secret))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up API token: %w", err)
+	}
+
+	// A failed last-used update must not fail the request
+	if err := s.repo.TouchAPIToken(token.ID); err != nil {
+		log.Printf("Failed to record use of API token %s: %v", token.ID, err)
+	}
+	return token, nil
+}
+
+// newAPITokenSecret generates a token secret with 256 bits of entropy
+func newAPITokenSecret() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return models.APITokenPrefix + base64.RawURLEncoding.EncodeToString(random), nil
+}
+
+// hashAPIToken returns the stored hash of a secret. Secrets are random, so a
+// fast hash is enough to make a leaked table useless.
+func hashAPIToken(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
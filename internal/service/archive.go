@@ -0,0 +1,302 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"roadmap/internal/models"
+	"roadmap/internal/repository"
+)
+
+// ErrInvalidArchive is returned for archives that cannot be imported
+var ErrInvalidArchive = errors.New("invalid archive")
+
+// ErrRoadmapNotEmpty is returned when importing into a roadmap with data without replace
+var ErrRoadmapNotEmpty = repository.ErrNotEmpty
+
+// ErrIDConflict is returned when archived ids are used by another roadmap
+var ErrIDConflict = repository.ErrIDConflict
+
+// exportAttempts bounds the re-reads of a roadmap changing during export
+const exportAttempts = 3
+
+// ExportArchive returns a backup of the roadmap, optionally with its retained
+// change log. The rows are read again when the roadmap changed meanwhile, so
+// that the archive is consistent with its version.
+func (s *Service) ExportArchive(documentID uuid.UUID, includeChangeLog bool) (*models.Archive, error) {
+	roadmap, err := s.GetRoadmap(documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		data, err := s.repo.GetAllData(documentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get all data: %w", err)
+		}
+		archive := archiveOf(roadmap, data)
+
+		if includeChangeLog {
+			logStart, err := s.repo.GetLogStartVersion(documentID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get log start version: %w", err)
+			}
+			changes, _, err := s.repo.GetChangesSince(documentID, logStart, 0)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get change log: %w", err)
+			}
+			archive.Roadmap.LogStartVersion = logStart
+			archive.ChangeLog = changes
+			if archive.ChangeLog == nil {
+				archive.ChangeLog = []models.ChangeLog{}
+			}
+		}
+
+		version, err := s.repo.GetCurrentVersion(documentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current version: %w", err)
+		}
+		if version == data.Version {
+			return archive, nil
+		}
+		if attempt == exportAttempts {
+			return nil, fmt.Errorf("roadmap %s changed during each of %d export attempts", documentID, exportAttempts)
+		}
+	}
+}
+
+// archiveOf converts the data of a roadmap into an archive
+func archiveOf(roadmap *models.Roadmap, data *models.DataResponse) *models.Archive {
+	archive := &models.Archive{
+		Format:        models.ArchiveFormat,
+		FormatVersion: models.ArchiveFormatVersion,
+		ExportedAt:    time.Now().UTC(),
+		Roadmap:       models.ArchiveRoadmap{ID: roadmap.ID, Name: roadmap.Name, Version: data.Version},
+		Teams:         data.Teams,
+		Sprints:       data.Sprints,
+		Resources:     make([]models.ArchiveResource, len(data.Resources)),
+		Tasks:         make([]models.ArchiveTask, len(data.Tasks)),
+	}
+	if archive.Teams == nil {
+		archive.Teams = []models.Team{}
+	}
+	if archive.Sprints == nil {
+		archive.Sprints = []models.Sprint{}
+	}
+
+	for i, resource := range data.Resources {
+		// Resource.TeamIDs holds team names for display
+		var teamIDs *pq.StringArray
+		if resource.TeamIDs != nil {
+			ids := resource.TeamUUIDs
+			if ids == nil {
+				ids = pq.StringArray{}
+			}
+			teamIDs = &ids
+		}
+		archive.Resources[i] = models.ArchiveResource{
+			ResourceUpdate: models.ResourceUpdate{
+				ID: resource.ID, TeamIDs: teamIDs, Function: resource.Function, Employee: resource.Employee,
+				FnBgColor: resource.FnBgColor, FnTextColor: resource.FnTextColor, Weeks: resource.Weeks,
+				PrevID: resource.PrevID, NextID: resource.NextID,
+			},
+			CreatedAt: resource.CreatedAt,
+		}
+	}
+	for i, task := range data.Tasks {
+		archive.Tasks[i] = models.ArchiveTask{
+			TaskUpdate: models.TaskUpdate{
+				ID: task.ID, Status: task.Status, SprintsAuto: task.SprintsAuto, Epic: task.Epic, TaskName: task.TaskName,
+				TeamID: task.TeamID, Function: task.Function, Employee: task.Employee, PlanEmpl: task.PlanEmpl,
+				PlanWeeks: task.PlanWeeks, BlockerIDs: task.BlockerIDs, WeekBlockers: task.WeekBlockers, Fact: task.Fact,
+				StartWeek: task.StartWeek, EndWeek: task.EndWeek, ExpectedStartWeek: task.ExpectedStartWeek,
+				AutoPlanEnabled: task.AutoPlanEnabled, Weeks: task.Weeks, PrevID: task.PrevID, NextID: task.NextID,
+			},
+			CreatedAt: task.CreatedAt,
+		}
+	}
+	return archive
+}
+
+// ImportArchive restores an archive into the roadmap in one transaction. Only
+// roadmap-wide admins may import, as it can replace every row. The archived
+// change log is not replayed: the restore is recorded as one new change set
+// and the result reports how many archived entries were left out.
+func (s *Service) ImportArchive(documentID uuid.UUID, archive *models.Archive, opts *models.ImportOptions) (*models.ImportResult, error) {
+	if s.accessControl {
+		perms, err := s.permissions(documentID, opts.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if !perms.allows(models.RoleAdmin, nil) {
+			return nil, fmt.Errorf("importing requires the roadmap-wide admin role: %w", ErrForbidden)
+		}
+	}
+	if err := validateArchive(archive); err != nil {
+		return nil, err
+	}
+	if opts.RemapIDs {
+		archive = remapArchive(archive)
+	}
+
+	restored, err := s.repo.RestoreArchive(documentID, archive, opts.Replace, opts.UserID, &opts.Meta)
+	if errors.Is(err, ErrRoadmapNotEmpty) {
+		return nil, fmt.Errorf("%w; import with replace to overwrite it", err)
+	}
+	if errors.Is(err, ErrIDConflict) {
+		return nil, fmt.Errorf("%w; import with remapped ids to make a copy", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore archive: %w", err)
+	}
+
+	version, err := s.repo.GetCurrentVersion(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current version: %w", err)
+	}
+	return &models.ImportResult{
+		DocumentID:       documentID,
+		Deleted:          restored.Deleted,
+		RevokedRoles:     restored.RevokedRoles,
+		Teams:            len(archive.Teams),
+		Sprints:          len(archive.Sprints),
+		Resources:        len(archive.Resources),
+		Tasks:            len(archive.Tasks),
+		RemappedIDs:      opts.RemapIDs,
+		Version:          version,
+		IgnoredChangeLog: len(archive.ChangeLog),
+	}, nil
+}
+
+// validateArchive checks the format, that ids are unique and that tasks
+// reference archived teams. Other references, such as blockers of deleted
+// tasks, are restored as they are.
+func validateArchive(archive *models.Archive) error {
+	if archive.Format != models.ArchiveFormat {
+		return fmt.Errorf("format %q, expected %q: %w", archive.Format, models.ArchiveFormat, ErrInvalidArchive)
+	}
+	if archive.FormatVersion < 1 || archive.FormatVersion > models.ArchiveFormatVersion {
+		return fmt.Errorf("unsupported format version %d, expected 1 to %d: %w",
+			archive.FormatVersion, models.ArchiveFormatVersion, ErrInvalidArchive)
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	unique := func(kind string, id uuid.UUID) error {
+		if id == uuid.Nil {
+			return fmt.Errorf("%s without id: %w", kind, ErrInvalidArchive)
+		}
+		if seen[id] {
+			return fmt.Errorf("duplicate %s id %s: %w", kind, id, ErrInvalidArchive)
+		}
+		seen[id] = true
+		return nil
+	}
+	for _, team := range archive.Teams {
+		if err := unique("team", team.ID); err != nil {
+			return err
+		}
+	}
+	for _, sprint := range archive.Sprints {
+		if err := unique("sprint", sprint.ID); err != nil {
+			return err
+		}
+	}
+	for _, resource := range archive.Resources {
+		if err := unique("resource", resource.ID); err != nil {
+			return err
+		}
+	}
+	for _, task := range archive.Tasks {
+		if err := unique("task", task.ID); err != nil {
+			return err
+		}
+	}
+
+	teams := make(map[uuid.UUID]bool, len(archive.Teams))
+	for _, team := range archive.Teams {
+		teams[team.ID] = true
+	}
+	for _, task := range archive.Tasks {
+		if task.TeamID != nil && !teams[*task.TeamID] {
+			return fmt.Errorf("task %s references team %s missing from the archive: %w", task.ID, *task.TeamID, ErrInvalidArchive)
+		}
+	}
+	return nil
+}
+
+// remapArchive returns a copy of the archive with new ids for every row and
+// references rewritten accordingly. References to rows outside the archive
+// are kept.
+func remapArchive(archive *models.Archive) *models.Archive {
+	ids := make(map[uuid.UUID]uuid.UUID)
+	remap := func(id uuid.UUID) uuid.UUID {
+		if _, ok := ids[id]; !ok {
+			ids[id] = uuid.New()
+		}
+		return ids[id]
+	}
+	remapStrings := func(values *pq.StringArray) *pq.StringArray {
+		if values == nil {
+			return nil
+		}
+		remapped := make(pq.StringArray, len(*values))
+		for i, value := range *values {
+			remapped[i] = value
+			if id, err := uuid.Parse(value); err == nil {
+				if newID, ok := ids[id]; ok {
+					remapped[i] = newID.String()
+				}
+			}
+		}
+		return &remapped
+	}
+
+	remapped := &models.Archive{
+		Format:        archive.Format,
+		FormatVersion: archive.FormatVersion,
+		ExportedAt:    archive.ExportedAt,
+		Roadmap:       archive.Roadmap,
+		Teams:         make([]models.Team, len(archive.Teams)),
+		Sprints:       make([]models.Sprint, len(archive.Sprints)),
+		Resources:     make([]models.ArchiveResource, len(archive.Resources)),
+		Tasks:         make([]models.ArchiveTask, len(archive.Tasks)),
+	}
+	// All ids are assigned before references are rewritten, as blockers may
+	// point to later tasks
+	for i, team := range archive.Teams {
+		team.ID = remap(team.ID)
+		remapped.Teams[i] = team
+	}
+	for i, sprint := range archive.Sprints {
+		sprint.ID = remap(sprint.ID)
+		remapped.Sprints[i] = sprint
+	}
+	for _, resource := range archive.Resources {
+		remap(resource.ID)
+	}
+	for _, task := range archive.Tasks {
+		remap(task.ID)
+	}
+
+	for i, resource := range archive.Resources {
+		resource.ID = ids[resource.ID]
+		resource.TeamIDs = remapStrings(resource.TeamIDs)
+		resource.PrevID, resource.NextID = nil, nil
+		remapped.Resources[i] = resource
+	}
+	for i, task := range archive.Tasks {
+		task.ID = ids[task.ID]
+		if task.TeamID != nil {
+			teamID := ids[*task.TeamID]
+			task.TeamID = &teamID
+		}
+		task.BlockerIDs = remapStrings(task.BlockerIDs)
+		task.PrevID, task.NextID = nil, nil
+		remapped.Tasks[i] = task
+	}
+	return remapped
+}
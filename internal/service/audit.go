@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+)
+
+// ErrInvalidFilter is returned for audit filters with unknown values
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// loggedModels are the API models of the tables recorded in change_log
+var loggedModels = map[string]reflect.Type{
+	"teams":     reflect.TypeOf(models.Team{}),
+	"sprints":   reflect.TypeOf(models.Sprint{}),
+	"resources": reflect.TypeOf(models.Resource{}),
+	"tasks":     reflect.TypeOf(models.Task{}),
+}
+
+// GetAuditLog returns a page of change_log entries matching the filter, newest
+// first. filter.Column may hold an API field name (endWeek) or a column name.
+func (s *Service) GetAuditLog(documentID uuid.UUID, filter *models.AuditFilter) (*models.AuditResponse, error) {
+	if err := normalizeAuditFilter(filter); err != nil {
+		return nil, err
+	}
+
+	entries, hasMore, err := s.repo.GetAuditEntries(documentID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit entries: %w", err)
+	}
+
+	response := &models.AuditResponse{Entries: entries, HasMore: hasMore}
+	if response.Entries == nil {
+		response.Entries = []models.AuditEntry{}
+	}
+	for i := range response.Entries {
+		entry := &response.Entries[i]
+		for j, column := range entry.ChangedFields {
+			entry.ChangedFields[j] = columnField(entry.TableName, column)
+		}
+	}
+	if hasMore {
+		cursor := entries[len(entries)-1].VersionNumber
+		response.NextCursor = &cursor
+	}
+
+	authors := make([]*string, len(entries))
+	for i := range entries {
+		authors[i] = entries[i].UserID
+	}
+	if response.Users, err = s.userDirectory(authors); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetAuditSummary returns the number of changes per user per day for entries
+// matching the filter
+func (s *Service) GetAuditSummary(documentID uuid.UUID, filter *models.AuditFilter) (*models.AuditSummaryResponse, error) {
+	if err := normalizeAuditFilter(filter); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.GetAuditSummary(documentID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit summary: %w", err)
+	}
+
+	authors := make([]*string, len(rows))
+	for i := range rows {
+		authors[i] = rows[i].UserID
+	}
+	users, err := s.userDirectory(authors)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AuditSummaryResponse{Rows: rows, Users: users}, nil
+}
+
+// normalizeAuditFilter validates the table and operation and resolves the
+// changed field to its column
+func normalizeAuditFilter(filter *models.AuditFilter) error {
+	if filter.Table != "" {
+		if _, ok := loggedModels[filter.Table]; !ok {
+			return fmt.Errorf("unknown table %q: %w", filter.Table, ErrInvalidFilter)
+		}
+	}
+
+	filter.Operation = strings.ToUpper(filter.Operation)
+	switch filter.Operation {
+	case "", "INSERT", "UPDATE", "DELETE":
+	default:
+		return fmt.Errorf("unknown operation %q: %w", filter.Operation, ErrInvalidFilter)
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return fmt.Errorf("from must be before to: %w", ErrInvalidFilter)
+	}
+
+	if filter.Column != "" {
+		column, ok := fieldColumn(filter.Table, filter.Column)
+		if !ok {
+			return fmt.Errorf("unknown field %q: %w", filter.Column, ErrInvalidFilter)
+		}
+		filter.Column = column
+	}
+	return nil
+}
+
+// fieldColumn resolves an API field or column name to the column of the table,
+// or of any logged table when table is empty
+func fieldColumn(table, name string) (string, bool) {
+	for modelTable, modelType := range loggedModels {
+		if table != "" && table != modelTable {
+			continue
+		}
+		for i := 0; i < modelType.NumField(); i++ {
+			column := modelType.Field(i).Tag.Get("db")
+			if column != "" && (column == name || columnField(modelTable, column) == name) {
+				return column, true
+			}
+		}
+	}
+	return "", false
+}
+
+// columnField returns the API field name of a column of the table, as
+// loggedRecordFields decodes it; unknown columns are returned as is
+func columnField(table, column string) string {
+	// team_ids is exposed as teamIds, team holds the display names
+	if table == "resources" && column == "team_ids" {
+		return "teamIds"
+	}
+
+	modelType, ok := loggedModels[table]
+	if !ok {
+		return column
+	}
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		if field.Tag.Get("db") != column {
+			continue
+		}
+		if name := strings.Split(field.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return column
+}
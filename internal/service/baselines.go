@@ -0,0 +1,205 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+)
+
+// GetBaselines returns all baselines of the document
+func (s *Service) GetBaselines(documentID uuid.UUID) ([]models.Baseline, error) {
+	baselines, err := s.repo.GetBaselines(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get baselines: %w", err)
+	}
+	return baselines, nil
+}
+
+// GetBaseline returns a baseline of the document or ErrNotFound
+func (s *Service) GetBaseline(documentID, id uuid.UUID) (*models.Baseline, error) {
+	baseline, err := s.repo.GetBaseline(documentID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("baseline %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get baseline %s: %w", id, err)
+	}
+	return baseline, nil
+}
+
+// CreateBaseline freezes the plan at the requested (by default current) version;
+// it requires the planner role
+func (s *Service) CreateBaseline(documentID uuid.UUID, req *models.CreateBaselineRequest) (*models.Baseline, error) {
+	if err := s.requireRole(documentID, req.UserID, models.RolePlanner); err != nil {
+		return nil, err
+	}
+
+	currentVersion, err := s.repo.GetCurrentVersion(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current version: %w", err)
+	}
+
+	version := currentVersion
+	if req.Version != nil {
+		version = *req.Version
+	}
+	if version < 0 || version > currentVersion {
+		return nil, fmt.Errorf("version %d is outside of 0..%d: %w", version, currentVersion, ErrInvalidVersion)
+	}
+	logStart, err := s.repo.GetLogStartVersion(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log start version: %w", err)
+	}
+	if version < logStart {
+		return nil, fmt.Errorf("version %d: %w", version, ErrVersionPruned)
+	}
+
+	baseline, err := s.repo.CreateBaseline(documentID, req.Name, version, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create baseline: %w", err)
+	}
+	return baseline, nil
+}
+
+// DeleteBaseline removes a baseline or returns ErrNotFound; it requires the
+// admin role
+func (s *Service) DeleteBaseline(documentID uuid.UUID, actorID string, id uuid.UUID) error {
+	if err := s.requireRole(documentID, actorID, models.RoleAdmin); err != nil {
+		return err
+	}
+
+	err := s.repo.DeleteBaseline(documentID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("baseline %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete baseline %s: %w", id, err)
+	}
+	return nil
+}
+
+// GetBaselineData returns the plan as it was at the baseline version
+func (s *Service) GetBaselineData(documentID, id uuid.UUID) (*models.DataResponse, error) {
+	baseline, err := s.GetBaseline(documentID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetDataAtVersion(documentID, baseline.Version)
+}
+
+// GetBaselineReport compares startWeek/endWeek/fact/sprintsAuto of every
+// current task with the baseline and flags slips, added and removed tasks
+func (s *Service) GetBaselineReport(documentID, id uuid.UUID) (*models.BaselineReport, error) {
+	baseline, err := s.GetBaseline(documentID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetAllData(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all data: %w", err)
+	}
+	frozen, err := s.GetDataAtVersion(documentID, baseline.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.BaselineReport{
+		Baseline:       *baseline,
+		CurrentVersion: current.Version,
+		Summary: map[models.BaselineTaskStatus]int{
+			models.BaselineTaskSlipped:   0,
+			models.BaselineTaskPulledIn:  0,
+			models.BaselineTaskChanged:   0,
+			models.BaselineTaskUnchanged: 0,
+			models.BaselineTaskAdded:     0,
+			models.BaselineTaskRemoved:   0,
+		},
+		Tasks: make([]models.BaselineTaskComparison, 0, len(current.Tasks)),
+	}
+
+	frozenByID := make(map[uuid.UUID]*models.Task, len(frozen.Tasks))
+	for i := range frozen.Tasks {
+		frozenByID[frozen.Tasks[i].ID] = &frozen.Tasks[i]
+	}
+	seen := make(map[uuid.UUID]bool, len(current.Tasks))
+	for i := range current.Tasks {
+		task := &current.Tasks[i]
+		seen[task.ID] = true
+		report.Tasks = append(report.Tasks, compareWithBaseline(task, frozenByID[task.ID], task))
+	}
+	for i := range frozen.Tasks {
+		if task := &frozen.Tasks[i]; !seen[task.ID] {
+			report.Tasks = append(report.Tasks, compareWithBaseline(task, task, nil))
+		}
+	}
+	for _, task := range report.Tasks {
+		report.Summary[task.Status]++
+	}
+
+	return report, nil
+}
+
+func compareWithBaseline(task, frozen, current *models.Task) models.BaselineTaskComparison {
+	comparison := models.BaselineTaskComparison{
+		ID:       task.ID,
+		Task:     task.TaskName,
+		Epic:     task.Epic,
+		Team:     task.Team,
+		Baseline: placement(frozen),
+		Current:  placement(current),
+	}
+
+	switch {
+	case comparison.Baseline == nil:
+		comparison.Status = models.BaselineTaskAdded
+		comparison.FactDelta = comparison.Current.Fact
+		return comparison
+	case comparison.Current == nil:
+		comparison.Status = models.BaselineTaskRemoved
+		comparison.FactDelta = -comparison.Baseline.Fact
+		return comparison
+	}
+
+	before, after := comparison.Baseline, comparison.Current
+	comparison.FactDelta = after.Fact - before.Fact
+	if before.StartWeek != nil && after.StartWeek != nil {
+		shift := *after.StartWeek - *before.StartWeek
+		comparison.StartShift = &shift
+	}
+	if before.EndWeek != nil && after.EndWeek != nil {
+		shift := *after.EndWeek - *before.EndWeek
+		comparison.EndShift = &shift
+	}
+
+	switch {
+	case comparison.EndShift != nil && *comparison.EndShift > 0:
+		comparison.Status = models.BaselineTaskSlipped
+	case comparison.EndShift != nil && *comparison.EndShift < 0:
+		comparison.Status = models.BaselineTaskPulledIn
+	case !equalWeek(before.StartWeek, after.StartWeek) ||
+		!equalWeek(before.EndWeek, after.EndWeek) ||
+		before.Fact != after.Fact ||
+		!equalStrings(before.SprintsAuto, after.SprintsAuto):
+		comparison.Status = models.BaselineTaskChanged
+	default:
+		comparison.Status = models.BaselineTaskUnchanged
+	}
+	return comparison
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
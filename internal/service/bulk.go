@@ -0,0 +1,380 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"roadmap/internal/models"
+)
+
+// BulkUpdateTasks applies a single operation to every task selected by id list
+// or filter. All changes are written in one transaction through UpdateData, so
+// the whole operation is one change set in change_log.
+func (s *Service) BulkUpdateTasks(documentID uuid.UUID, req *models.BulkTaskRequest) (*models.BulkTaskResponse, error) {
+	// An empty filter would match every task
+	if len(req.IDs) == 0 && emptyBulkFilter(req.Filter) {
+		return &models.BulkTaskResponse{
+			Success: false,
+			Error:   "Either ids or a filter with at least one field must be provided",
+		}, nil
+	}
+	if msg := validateBulkArguments(req); msg != "" {
+		return &models.BulkTaskResponse{Success: false, Error: msg}, nil
+	}
+
+	tasks, err := s.repo.GetTasks(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	targets := selectBulkTargets(tasks, req)
+	if req.Operation == models.BulkOpAddBlocker {
+		if msg := validateBlockerPosition(tasks, targets, *req.BlockerID); msg != "" {
+			return &models.BulkTaskResponse{Success: false, Error: msg}, nil
+		}
+	}
+	if req.Operation == models.BulkOpShiftWeeks {
+		if msg := validateShift(targets, *req.Shift); msg != "" {
+			return &models.BulkTaskResponse{Success: false, Error: msg}, nil
+		}
+	}
+	affected := []uuid.UUID{}
+
+	update := &models.UpdateRequest{
+		Version: req.Version,
+		UserID:  req.UserID,
+		Meta:    req.Meta,
+	}
+	if req.Operation == models.BulkOpDelete {
+		for _, task := range targets {
+			affected = append(affected, task.ID)
+		}
+		if len(affected) > 0 {
+			update.Tasks = buildDeleteUpdates(tasks, targets)
+			update.Deleted = map[string][]uuid.UUID{"tasks": affected}
+		}
+	} else {
+		for _, task := range targets {
+			if taskUpdate, changed := buildBulkTaskUpdate(task, req); changed {
+				update.Tasks = append(update.Tasks, taskUpdate)
+				affected = append(affected, task.ID)
+			}
+		}
+	}
+
+	// Nothing to change: report success without touching the version
+	if len(affected) == 0 {
+		return &models.BulkTaskResponse{
+			Version:  req.Version,
+			Success:  true,
+			Affected: []uuid.UUID{},
+		}, nil
+	}
+
+	response, err := s.UpdateData(documentID, update)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BulkTaskResponse{
+		Version:   response.Version,
+		Success:   response.Success,
+		Error:     response.Error,
+		Forbidden: response.Forbidden,
+		Affected:  affected,
+	}, nil
+}
+
+// validateBulkArguments checks that the operation is known and has its arguments
+func validateBulkArguments(req *models.BulkTaskRequest) string {
+	switch req.Operation {
+	case models.BulkOpSetStatus:
+		if req.Status == nil {
+			return "status is required for setStatus"
+		}
+		switch *req.Status {
+		case models.TaskStatusTodo, models.TaskStatusBacklog, models.TaskStatusCancelled:
+		default:
+			return fmt.Sprintf("Unknown status: %s", *req.Status)
+		}
+	case models.BulkOpReassign:
+		if req.TeamID == nil && req.Function == nil && req.Employee == nil {
+			return "At least one of teamId, fn, empl is required for reassign"
+		}
+	case models.BulkOpShiftWeeks:
+		if req.Shift == nil || *req.Shift == 0 {
+			return "Non-zero shift is required for shiftWeeks"
+		}
+	case models.BulkOpSetAutoPlan:
+		if req.AutoPlanEnabled == nil {
+			return "autoPlanEnabled is required for setAutoPlan"
+		}
+	case models.BulkOpAddBlocker, models.BulkOpRemoveBlocker:
+		if req.BlockerID == nil {
+			return fmt.Sprintf("blockerId is required for %s", req.Operation)
+		}
+	case models.BulkOpDelete:
+	default:
+		return fmt.Sprintf("Unknown operation: %s", req.Operation)
+	}
+	return ""
+}
+
+// validateBlockerPosition enforces spec §5.2: a blocker must exist and be located
+// above every task it blocks. Since all edges point upwards, no cycle can appear.
+func validateBlockerPosition(tasks []models.Task, targets []models.Task, blockerID uuid.UUID) string {
+	position := make(map[uuid.UUID]int, len(tasks))
+	for i, task := range tasks {
+		position[task.ID] = i
+	}
+	blockerPos, ok := position[blockerID]
+	if !ok {
+		return fmt.Sprintf("Blocker task %s not found", blockerID)
+	}
+	for _, task := range targets {
+		if task.ID != blockerID && position[task.ID] < blockerPos {
+			return fmt.Sprintf("Blocker %s is located below task %s", blockerID, task.ID)
+		}
+	}
+	return ""
+}
+
+// validateShift refuses shifts that would move planned weeks of a task before
+// the first week: their load would be lost and the fact would change
+func validateShift(targets []models.Task, shift int) string {
+	if shift >= 0 {
+		return ""
+	}
+	for _, task := range targets {
+		if task.Weeks == nil {
+			continue
+		}
+		for i, value := range *task.Weeks {
+			if i >= -shift {
+				break
+			}
+			if value != 0 {
+				return fmt.Sprintf("Shift by %d weeks moves week %d of task %s before the first week", shift, i+1, task.ID)
+			}
+		}
+	}
+	return ""
+}
+
+// selectBulkTargets returns tasks matching the id list or filter, in table order
+func selectBulkTargets(tasks []models.Task, req *models.BulkTaskRequest) []models.Task {
+	ids := make(map[uuid.UUID]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		ids[id] = true
+	}
+
+	var targets []models.Task
+	for _, task := range tasks {
+		if len(ids) > 0 && !ids[task.ID] {
+			continue
+		}
+		if req.Filter != nil && !matchesBulkFilter(task, req.Filter) {
+			continue
+		}
+		targets = append(targets, task)
+	}
+	return targets
+}
+
+func emptyBulkFilter(filter *models.BulkTaskFilter) bool {
+	return filter == nil || (filter.Epic == nil && filter.TeamID == nil && filter.Function == nil &&
+		filter.Employee == nil && filter.Status == nil)
+}
+
+func matchesBulkFilter(task models.Task, filter *models.BulkTaskFilter) bool {
+	if filter.Epic != nil && (task.Epic == nil || *task.Epic != *filter.Epic) {
+		return false
+	}
+	if filter.TeamID != nil && (task.TeamID == nil || *task.TeamID != *filter.TeamID) {
+		return false
+	}
+	if filter.Function != nil && (task.Function == nil || *task.Function != *filter.Function) {
+		return false
+	}
+	if filter.Employee != nil && (task.Employee == nil || *task.Employee != *filter.Employee) {
+		return false
+	}
+	if filter.Status != nil && (task.Status == nil || *task.Status != *filter.Status) {
+		return false
+	}
+	return true
+}
+
+// buildBulkTaskUpdate converts the operation into a partial update of one task.
+// It reports false when the task is already in the requested state.
+func buildBulkTaskUpdate(task models.Task, req *models.BulkTaskRequest) (models.TaskUpdate, bool) {
+	update := models.TaskUpdate{ID: task.ID}
+
+	switch req.Operation {
+	case models.BulkOpSetStatus:
+		if task.Status != nil && *task.Status == *req.Status {
+			return update, false
+		}
+		update.Status = req.Status
+
+	case models.BulkOpReassign:
+		update.TeamID = req.TeamID
+		update.Function = req.Function
+		update.Employee = req.Employee
+
+	case models.BulkOpShiftWeeks:
+		if task.Weeks == nil {
+			return update, false
+		}
+		weeks := shiftWeeks(*task.Weeks, *req.Shift)
+		update.Weeks = &weeks
+		fact, startWeek, endWeek := weeksSummary(weeks)
+		update.Fact = &fact
+		update.StartWeek = startWeek
+		update.EndWeek = endWeek
+		if task.ExpectedStartWeek != nil {
+			expected := *task.ExpectedStartWeek + *req.Shift
+			if expected < 1 {
+				expected = 1
+			}
+			update.ExpectedStartWeek = &expected
+		}
+		// A manual move of the plan disables auto-planning (spec §3.8)
+		autoPlan := false
+		update.AutoPlanEnabled = &autoPlan
+
+	case models.BulkOpSetAutoPlan:
+		if task.AutoPlanEnabled != nil && *task.AutoPlanEnabled == *req.AutoPlanEnabled {
+			return update, false
+		}
+		update.AutoPlanEnabled = req.AutoPlanEnabled
+
+	case models.BulkOpAddBlocker:
+		if *req.BlockerID == task.ID {
+			return update, false
+		}
+		blockers := pq.StringArray{}
+		if task.BlockerIDs != nil {
+			for _, id := range *task.BlockerIDs {
+				if id == req.BlockerID.String() {
+					return update, false
+				}
+				blockers = append(blockers, id)
+			}
+		}
+		blockers = append(blockers, req.BlockerID.String())
+		update.BlockerIDs = &blockers
+
+	case models.BulkOpRemoveBlocker:
+		if task.BlockerIDs == nil {
+			return update, false
+		}
+		blockers, removed := withoutBlockers(*task.BlockerIDs, map[string]bool{req.BlockerID.String(): true})
+		if !removed {
+			return update, false
+		}
+		update.BlockerIDs = &blockers
+	}
+
+	return update, true
+}
+
+// buildDeleteUpdates relinks the ordered list around the deleted tasks and
+// removes references to them from the blockers of the remaining tasks (spec §8).
+func buildDeleteUpdates(tasks []models.Task, targets []models.Task) []models.TaskUpdate {
+	deleted := make(map[uuid.UUID]bool, len(targets))
+	deletedStr := make(map[string]bool, len(targets))
+	for _, task := range targets {
+		deleted[task.ID] = true
+		deletedStr[task.ID.String()] = true
+	}
+
+	updates := make(map[uuid.UUID]*models.TaskUpdate)
+	getUpdate := func(id uuid.UUID) *models.TaskUpdate {
+		if u, ok := updates[id]; ok {
+			return u
+		}
+		u := &models.TaskUpdate{ID: id}
+		updates[id] = u
+		return u
+	}
+
+	// Surviving tasks in list order; pointers left dangling by the deletion
+	// are cleared by ON DELETE SET NULL, so only non-nil links are written.
+	var survivors []models.Task
+	for _, task := range tasks {
+		if !deleted[task.ID] {
+			survivors = append(survivors, task)
+		}
+	}
+	for i, task := range survivors {
+		if i > 0 && task.PrevID != nil && deleted[*task.PrevID] {
+			prevID := survivors[i-1].ID
+			getUpdate(task.ID).PrevID = &prevID
+		}
+		if i < len(survivors)-1 && task.NextID != nil && deleted[*task.NextID] {
+			nextID := survivors[i+1].ID
+			getUpdate(task.ID).NextID = &nextID
+		}
+		if task.BlockerIDs != nil {
+			if blockers, removed := withoutBlockers(*task.BlockerIDs, deletedStr); removed {
+				getUpdate(task.ID).BlockerIDs = &blockers
+			}
+		}
+	}
+
+	result := make([]models.TaskUpdate, 0, len(updates))
+	for _, task := range survivors {
+		if u, ok := updates[task.ID]; ok {
+			result = append(result, *u)
+		}
+	}
+	return result
+}
+
+// withoutBlockers returns blockers without the given ids and whether any were removed
+func withoutBlockers(blockers pq.StringArray, remove map[string]bool) (pq.StringArray, bool) {
+	result := pq.StringArray{}
+	for _, id := range blockers {
+		if !remove[id] {
+			result = append(result, id)
+		}
+	}
+	return result, len(result) != len(blockers)
+}
+
+// shiftWeeks moves week values by shift positions. The array grows when shifted
+// right; values shifted before week #1 are dropped.
+func shiftWeeks(weeks pq.Float64Array, shift int) pq.Float64Array {
+	length := len(weeks)
+	if shift > 0 {
+		length += shift
+	}
+	result := make(pq.Float64Array, length)
+	for i, value := range weeks {
+		j := i + shift
+		if j >= 0 && j < len(result) {
+			result[j] = value
+		}
+	}
+	return result
+}
+
+// weeksSummary computes fact and 1-based start/end weeks (spec §3.10)
+func weeksSummary(weeks pq.Float64Array) (float64, *int, *int) {
+	var fact float64
+	var startWeek, endWeek *int
+	for i, value := range weeks {
+		fact += value
+		if value > 0 {
+			week := i + 1
+			if startWeek == nil {
+				startWeek = &week
+			}
+			endWeek = &week
+		}
+	}
+	return fact, startWeek, endWeek
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"fmt"
+
+	"roadmap/internal/models"
+)
+
+// unknownAuthorNote annotates changes logged while the trigger did not record
+// the author (migrations 005 to 010)
+const unknownAuthorNote = "Author unknown: attribution was not recorded for these changes"
+
+// AnnotateAttributionGap marks changes without a recorded author as unknown by
+// grouping them into an annotated change set per roadmap
+func (s *Service) AnnotateAttributionGap() ([]models.AttributionGap, error) {
+	gaps, err := s.repo.AnnotateUnattributedChanges(unknownAuthorNote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to annotate unattributed changes: %w", err)
+	}
+	return gaps, nil
+}
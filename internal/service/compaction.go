@@ -0,0 +1,50 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"roadmap/internal/models"
+)
+
+// CompactChangeLogs snapshots every document and prunes change_log entries
+// older than the retention. Documents with nothing to prune are skipped.
+func (s *Service) CompactChangeLogs(retention time.Duration) ([]models.CompactionResult, error) {
+	roadmaps, err := s.repo.GetRoadmaps("")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roadmaps: %w", err)
+	}
+
+	before := time.Now().Add(-retention)
+	results := []models.CompactionResult{}
+	for _, roadmap := range roadmaps {
+		result, err := s.repo.CompactChangeLog(roadmap.ID, before)
+		if err != nil {
+			return results, fmt.Errorf("failed to compact roadmap %s: %w", roadmap.ID, err)
+		}
+		if result != nil {
+			results = append(results, *result)
+		}
+	}
+	return results, nil
+}
+
+// RunChangeLogCompaction compacts change logs every interval until the
+// process exits. Errors are logged and retried on the next tick.
+func (s *Service) RunChangeLogCompaction(interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		results, err := s.CompactChangeLogs(retention)
+		if err != nil {
+			log.Printf("Change log compaction failed: %v", err)
+		}
+		for _, result := range results {
+			log.Printf("Compacted change log of roadmap %s: snapshot at version %d, pruned %d changes",
+				result.DocumentID, result.SnapshotVersion, result.PrunedChanges)
+		}
+		<-ticker.C
+	}
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"roadmap/internal/models"
+)
+
+// DuplicateTask copies a task (or every task of its epic) and places the
+// copies below the original as a contiguous block (spec §8). Blocker edges
+// between duplicated tasks are remapped to the copies.
+func (s *Service) DuplicateTask(documentID, id uuid.UUID, req *models.DuplicateRequest) (*models.DuplicateResponse, error) {
+	tasks, err := s.repo.GetTasks(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	position := -1
+	for i, task := range tasks {
+		if task.ID == id {
+			position = i
+			break
+		}
+	}
+	if position < 0 {
+		return nil, fmt.Errorf("task %s: %w", id, ErrNotFound)
+	}
+
+	// Collect originals in table order; the block is inserted after the last one
+	originals := []models.Task{tasks[position]}
+	anchor := position
+	if req.Epic {
+		epic := tasks[position].Epic
+		if epic == nil || *epic == "" {
+			return &models.DuplicateResponse{
+				Success: false,
+				Error:   "Task has no epic to duplicate",
+			}, nil
+		}
+		originals = originals[:0]
+		for i, task := range tasks {
+			if task.Epic != nil && *task.Epic == *epic {
+				originals = append(originals, task)
+				anchor = i
+			}
+		}
+	}
+
+	idMap := make(map[uuid.UUID]uuid.UUID, len(originals))
+	strMap := make(map[string]string, len(originals))
+	for _, task := range originals {
+		copyID := uuid.New()
+		idMap[task.ID] = copyID
+		strMap[task.ID.String()] = copyID.String()
+	}
+
+	update := &models.UpdateRequest{
+		Version: req.Version,
+		UserID:  req.UserID,
+		Meta:    req.Meta,
+	}
+
+	// Build copies chained one after another
+	anchorTask := tasks[anchor]
+	for i, task := range originals {
+		copied := taskToUpdate(task)
+		copied.ID = idMap[task.ID]
+		if req.EpicName != nil {
+			copied.Epic = req.EpicName
+		}
+		if !req.CopyWeeks {
+			clearTaskWeeks(&copied)
+		}
+		if copied.BlockerIDs != nil {
+			blockers := make(pq.StringArray, len(*copied.BlockerIDs))
+			for j, blockerID := range *copied.BlockerIDs {
+				if mapped, ok := strMap[blockerID]; ok {
+					blockers[j] = mapped
+				} else {
+					blockers[j] = blockerID
+				}
+			}
+			copied.BlockerIDs = &blockers
+		}
+
+		if i == 0 {
+			copied.PrevID = &anchorTask.ID
+		} else {
+			prevID := idMap[originals[i-1].ID]
+			copied.PrevID = &prevID
+		}
+		if i < len(originals)-1 {
+			nextID := idMap[originals[i+1].ID]
+			copied.NextID = &nextID
+		} else {
+			copied.NextID = anchorTask.NextID
+		}
+		update.Tasks = append(update.Tasks, copied)
+	}
+
+	firstCopyID := idMap[originals[0].ID]
+	lastCopyID := idMap[originals[len(originals)-1].ID]
+	update.Tasks = append(update.Tasks, models.TaskUpdate{ID: anchorTask.ID, NextID: &firstCopyID})
+	if anchorTask.NextID != nil {
+		update.Tasks = append(update.Tasks, models.TaskUpdate{ID: *anchorTask.NextID, PrevID: &lastCopyID})
+	}
+
+	// Re-point dependents located below the inserted block to the copies
+	if req.RepointDependents {
+		for _, task := range tasks[anchor+1:] {
+			if _, duplicated := idMap[task.ID]; duplicated || task.BlockerIDs == nil {
+				continue
+			}
+			blockers := make(pq.StringArray, len(*task.BlockerIDs))
+			changed := false
+			for j, blockerID := range *task.BlockerIDs {
+				if mapped, ok := strMap[blockerID]; ok {
+					blockers[j] = mapped
+					changed = true
+				} else {
+					blockers[j] = blockerID
+				}
+			}
+			if changed {
+				mergeTaskUpdate(update, models.TaskUpdate{ID: task.ID, BlockerIDs: &blockers})
+			}
+		}
+	}
+
+	response, err := s.UpdateData(documentID, update)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DuplicateResponse{
+		Version:   response.Version,
+		Success:   response.Success,
+		Error:     response.Error,
+		Forbidden: response.Forbidden,
+		IDMap:     idMap,
+	}, nil
+}
+
+// DuplicateResource copies a resource row and places the copy below the original
+func (s *Service) DuplicateResource(documentID, id uuid.UUID, req *models.DuplicateRequest) (*models.DuplicateResponse, error) {
+	resources, err := s.repo.GetResources(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resources: %w", err)
+	}
+
+	var original *models.Resource
+	for i := range resources {
+		if resources[i].ID == id {
+			original = &resources[i]
+			break
+		}
+	}
+	if original == nil {
+		return nil, fmt.Errorf("resource %s: %w", id, ErrNotFound)
+	}
+
+	copied := resourceToUpdate(*original)
+	copied.ID = uuid.New()
+	copied.PrevID = &original.ID
+	copied.NextID = original.NextID
+	if !req.CopyWeeks && copied.Weeks != nil {
+		weeks := make(pq.Float64Array, len(*copied.Weeks))
+		copied.Weeks = &weeks
+	}
+
+	update := &models.UpdateRequest{
+		Version:   req.Version,
+		UserID:    req.UserID,
+		Meta:      req.Meta,
+		Resources: []models.ResourceUpdate{copied, {ID: original.ID, NextID: &copied.ID}},
+	}
+	if original.NextID != nil {
+		update.Resources = append(update.Resources, models.ResourceUpdate{ID: *original.NextID, PrevID: &copied.ID})
+	}
+
+	response, err := s.UpdateData(documentID, update)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DuplicateResponse{
+		Version:   response.Version,
+		Success:   response.Success,
+		Error:     response.Error,
+		Forbidden: response.Forbidden,
+		IDMap:     map[uuid.UUID]uuid.UUID{original.ID: copied.ID},
+	}, nil
+}
+
+// taskToUpdate converts a stored task into a full update carrying all its fields
+func taskToUpdate(task models.Task) models.TaskUpdate {
+	return models.TaskUpdate{
+		ID:                task.ID,
+		Status:            task.Status,
+		SprintsAuto:       task.SprintsAuto,
+		Epic:              task.Epic,
+		TaskName:          task.TaskName,
+		TeamID:            task.TeamID,
+		Function:          task.Function,
+		Employee:          task.Employee,
+		PlanEmpl:          task.PlanEmpl,
+		PlanWeeks:         task.PlanWeeks,
+		BlockerIDs:        task.BlockerIDs,
+		WeekBlockers:      task.WeekBlockers,
+		Fact:              task.Fact,
+		StartWeek:         task.StartWeek,
+		EndWeek:           task.EndWeek,
+		ExpectedStartWeek: task.ExpectedStartWeek,
+		AutoPlanEnabled:   task.AutoPlanEnabled,
+		Weeks:             task.Weeks,
+		PrevID:            task.PrevID,
+		NextID:            task.NextID,
+	}
+}
+
+// resourceToUpdate converts a stored resource into a full update carrying all its fields.
+// Team UUIDs are used since TeamIDs holds display names after loading.
+func resourceToUpdate(resource models.Resource) models.ResourceUpdate {
+	update := models.ResourceUpdate{
+		ID:          resource.ID,
+		Function:    resource.Function,
+		Employee:    resource.Employee,
+		FnBgColor:   resource.FnBgColor,
+		FnTextColor: resource.FnTextColor,
+		Weeks:       resource.Weeks,
+		PrevID:      resource.PrevID,
+		NextID:      resource.NextID,
+	}
+	if resource.TeamUUIDs != nil {
+		teamIDs := make(pq.StringArray, len(resource.TeamUUIDs))
+		copy(teamIDs, resource.TeamUUIDs)
+		update.TeamIDs = &teamIDs
+	}
+	return update
+}
+
+// clearTaskWeeks resets the placement of a task copy, keeping the weeks length
+func clearTaskWeeks(task *models.TaskUpdate) {
+	if task.Weeks != nil {
+		weeks := make(pq.Float64Array, len(*task.Weeks))
+		task.Weeks = &weeks
+	}
+	fact := 0.0
+	task.Fact = &fact
+	task.StartWeek = nil
+	task.EndWeek = nil
+	task.SprintsAuto = &pq.StringArray{}
+}
+
+// mergeTaskUpdate adds a task update, merging set fields into an existing
+// update of the same task so each row is written once
+func mergeTaskUpdate(req *models.UpdateRequest, update models.TaskUpdate) {
+	for i := range req.Tasks {
+		if req.Tasks[i].ID != update.ID {
+			continue
+		}
+		existing := &req.Tasks[i]
+		if update.BlockerIDs != nil {
+			existing.BlockerIDs = update.BlockerIDs
+		}
+		if update.PrevID != nil {
+			existing.PrevID = update.PrevID
+		}
+		if update.NextID != nil {
+			existing.NextID = update.NextID
+		}
+		return
+	}
+	req.Tasks = append(req.Tasks, update)
+}
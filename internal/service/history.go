@@ -0,0 +1,240 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+	"roadmap/internal/repository"
+)
+
+// ErrInvalidVersion is returned for versions outside of the document history
+var ErrInvalidVersion = errors.New("invalid version")
+
+// ErrVersionPruned is returned for versions before the retained change log
+var ErrVersionPruned = errors.New("version was compacted")
+
+// GetDataAtVersion reconstructs the document as it was at the given version
+// from change_log and compaction snapshots
+func (s *Service) GetDataAtVersion(documentID uuid.UUID, version int64) (*models.DataResponse, error) {
+	currentVersion, err := s.repo.GetCurrentVersion(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current version: %w", err)
+	}
+	if version < 0 || version > currentVersion {
+		return nil, fmt.Errorf("version %d is outside of 0..%d: %w", version, currentVersion, ErrInvalidVersion)
+	}
+	if version == currentVersion {
+		return s.GetAllData(documentID)
+	}
+
+	rows, err := s.repo.GetRowsAtVersion(documentID, version)
+	if errors.Is(err, repository.ErrHistoryPruned) {
+		return nil, fmt.Errorf("version %d: %w", version, ErrVersionPruned)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to reconstruct version %d: %w", version, err)
+	}
+
+	data := &models.DataResponse{Version: version}
+	for table, byID := range rows {
+		for id, state := range byID {
+			switch table {
+			case "teams":
+				data.Teams, err = appendLoggedRow(data.Teams, state)
+			case "sprints":
+				data.Sprints, err = appendLoggedRow(data.Sprints, state)
+			case "resources":
+				data.Resources, err = appendLoggedRow(data.Resources, state)
+			case "tasks":
+				data.Tasks, err = appendLoggedRow(data.Tasks, state)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to restore %s %s: %w", table, id, err)
+			}
+		}
+	}
+
+	// The team_ids column holds UUIDs; names are resolved by refreshDisplayNames
+	for i := range data.Resources {
+		if data.Resources[i].TeamIDs != nil {
+			data.Resources[i].TeamUUIDs = append([]string(nil), *data.Resources[i].TeamIDs...)
+		}
+	}
+
+	// Same ordering as the repository; creation order keeps rows outside of
+	// the linked lists stable
+	sort.SliceStable(data.Resources, func(i, j int) bool {
+		return data.Resources[i].CreatedAt.Before(data.Resources[j].CreatedAt)
+	})
+	sort.SliceStable(data.Tasks, func(i, j int) bool {
+		return data.Tasks[i].CreatedAt.Before(data.Tasks[j].CreatedAt)
+	})
+	sort.SliceStable(data.Teams, func(i, j int) bool {
+		return valueOrEmpty(data.Teams[i].Name) < valueOrEmpty(data.Teams[j].Name)
+	})
+	sort.SliceStable(data.Sprints, func(i, j int) bool {
+		return valueOrEmpty(data.Sprints[i].StartDate) < valueOrEmpty(data.Sprints[j].StartDate)
+	})
+	refreshDisplayNames(data)
+	data.Resources = orderByLinks(data.Resources, func(r models.Resource) (uuid.UUID, *uuid.UUID, *uuid.UUID) {
+		return r.ID, r.PrevID, r.NextID
+	})
+	data.Tasks = orderByLinks(data.Tasks, func(t models.Task) (uuid.UUID, *uuid.UUID, *uuid.UUID) {
+		return t.ID, t.PrevID, t.NextID
+	})
+	return data, nil
+}
+
+// GetVersionDiff returns the net change of every record between two versions
+// with per-field old and new values. Several changes of the same record are
+// collapsed into one; records that ended up unchanged are omitted.
+// A nil to means the current version.
+func (s *Service) GetVersionDiff(documentID uuid.UUID, fromVersion int64, to *int64) (*models.VersionDiffResponse, error) {
+	currentVersion, err := s.repo.GetCurrentVersion(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current version: %w", err)
+	}
+	toVersion := currentVersion
+	if to != nil {
+		toVersion = *to
+	}
+	if fromVersion < 0 || fromVersion > toVersion || toVersion > currentVersion {
+		return nil, fmt.Errorf("versions must satisfy 0 <= from (%d) <= to (%d) <= %d: %w",
+			fromVersion, toVersion, currentVersion, ErrInvalidVersion)
+	}
+
+	records, err := s.repo.GetRecordChanges(documentID, fromVersion, toVersion)
+	if errors.Is(err, repository.ErrHistoryPruned) {
+		return nil, fmt.Errorf("version %d: %w", fromVersion, ErrVersionPruned)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get record changes: %w", err)
+	}
+
+	changes := []models.RecordChange{}
+	for _, record := range records {
+		before, err := loggedRecordFields(record.Table, record.Before)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s %s: %w", record.Table, record.RecordID, err)
+		}
+		after, err := loggedRecordFields(record.Table, record.After)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s %s: %w", record.Table, record.RecordID, err)
+		}
+
+		change := models.RecordChange{Table: record.Table, RecordID: record.RecordID, Fields: diffFields(before, after)}
+		switch {
+		case before == nil && after == nil:
+			continue
+		case before == nil:
+			change.Operation = "INSERT"
+		case after == nil:
+			change.Operation = "DELETE"
+		case len(change.Fields) == 0:
+			continue
+		default:
+			change.Operation = "UPDATE"
+		}
+		changes = append(changes, change)
+	}
+
+	return &models.VersionDiffResponse{
+		From:    fromVersion,
+		To:      toVersion,
+		Changes: changes,
+	}, nil
+}
+
+// loggedRecordFields decodes a logged row into the API representation of its
+// table as a JSON object, or nil when the record does not exist
+func loggedRecordFields(table string, state interface{}) (map[string]interface{}, error) {
+	if state == nil {
+		return nil, nil
+	}
+
+	var row interface{}
+	switch table {
+	case "teams":
+		row = &models.Team{}
+	case "sprints":
+		row = &models.Sprint{}
+	case "resources":
+		row = &models.Resource{}
+	case "tasks":
+		row = &models.Task{}
+	default:
+		return nil, fmt.Errorf("unknown table %s", table)
+	}
+	if err := decodeLoggedRow(state, row); err != nil {
+		return nil, err
+	}
+	if resource, ok := row.(*models.Resource); ok && resource.TeamIDs != nil {
+		resource.TeamUUIDs = append([]string(nil), *resource.TeamIDs...)
+	}
+
+	data, err := json.Marshal(row)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// appendLoggedRow decodes a row from its change_log JSON and appends it
+func appendLoggedRow[T any](rows []T, state interface{}) ([]T, error) {
+	var row T
+	if err := decodeLoggedRow(state, &row); err != nil {
+		return nil, err
+	}
+	return append(rows, row), nil
+}
+
+// decodeLoggedRow decodes a row logged by the log_data_change trigger, whose
+// keys are column names, into a model by matching keys with its db tags
+func decodeLoggedRow(state interface{}, dst interface{}) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	var columns map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &columns); err != nil {
+		return err
+	}
+
+	fields := make(map[string]json.RawMessage, len(columns))
+	modelType := reflect.TypeOf(dst).Elem()
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		column := field.Tag.Get("db")
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if column == "" || name == "" || name == "-" {
+			continue
+		}
+		if value, ok := columns[column]; ok {
+			fields[name] = value
+		}
+	}
+
+	mapped, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(mapped, dst)
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
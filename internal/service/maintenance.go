@@ -0,0 +1,232 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+	"roadmap/internal/planning"
+)
+
+// Replan re-runs auto-planning on the server and saves weeks, fact,
+// startWeek, endWeek and sprintsAuto of every task whose plan changed, as the
+// client does after an edit
+func (s *Service) Replan(documentID uuid.UUID, userID string) (*models.ReplanResult, error) {
+	data, err := s.repo.GetAllData(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all data: %w", err)
+	}
+
+	plan := planning.Compute(data)
+	result := &models.ReplanResult{Version: data.Version, Weeks: plan.Calendar.Weeks}
+
+	req := &models.UpdateRequest{Version: data.Version, UserID: userID, Meta: models.ChangeSetMeta{Note: "Replanned on the server"}}
+	// Tasks left without a plan lose their start and end weeks, which the
+	// partial update keeps when they are nil
+	var cleared []uuid.UUID
+	for i := range data.Tasks {
+		if update, changed := replannedTask(&data.Tasks[i], &plan.Tasks[i], plan.Calendar.Weeks); changed {
+			req.Tasks = append(req.Tasks, update)
+			if update.StartWeek == nil || update.EndWeek == nil {
+				cleared = append(cleared, update.ID)
+			}
+		}
+	}
+	if len(req.Tasks) == 0 {
+		return result, nil
+	}
+
+	response, err := s.updateData(documentID, req, func(tx *sql.Tx) error {
+		if len(cleared) == 0 {
+			return nil
+		}
+		return s.repo.ClearTaskWeeks(tx, documentID, cleared)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !response.Success {
+		return nil, fmt.Errorf("failed to save plan: %s", response.Error)
+	}
+	result.ChangedTasks = len(req.Tasks)
+	result.Version = response.Version
+	return result, nil
+}
+
+// replannedTask returns the update saving the computed plan of a task and
+// whether it differs from the stored one
+func replannedTask(stored, computed *models.Task, weeks int) (models.TaskUpdate, bool) {
+	var storedWeeks []float64
+	if stored.Weeks != nil {
+		storedWeeks = *stored.Weeks
+	}
+	storedFact := 0.0
+	if stored.Fact != nil {
+		storedFact = *stored.Fact
+	}
+
+	changed := !equalFloats(padWeeks(storedWeeks, weeks), *computed.Weeks) ||
+		storedFact != *computed.Fact ||
+		!equalWeek(stored.StartWeek, computed.StartWeek) ||
+		!equalWeek(stored.EndWeek, computed.EndWeek) ||
+		!equalStrings(sprintsOf(stored), *computed.SprintsAuto)
+
+	return models.TaskUpdate{
+		ID:          stored.ID,
+		Weeks:       computed.Weeks,
+		Fact:        computed.Fact,
+		StartWeek:   computed.StartWeek,
+		EndWeek:     computed.EndWeek,
+		SprintsAuto: computed.SprintsAuto,
+	}, changed
+}
+
+// RepairOrder rebuilds the prev/next lists of resources and tasks when they
+// are broken: rows missing from the list, several heads, dangling links or
+// cycles. The intact part of the list keeps its order; the rest follows it in
+// creation order.
+func (s *Service) RepairOrder(documentID uuid.UUID, userID string) (*models.RepairOrderResult, error) {
+	resources, err := s.repo.GetResources(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resources: %w", err)
+	}
+	tasks, err := s.repo.GetTasks(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	resourceLinks := make([]orderLink, len(resources))
+	for i, resource := range resources {
+		resourceLinks[i] = orderLink{resource.ID, resource.PrevID, resource.NextID, resource.CreatedAt}
+	}
+	taskLinks := make([]orderLink, len(tasks))
+	for i, task := range tasks {
+		taskLinks[i] = orderLink{task.ID, task.PrevID, task.NextID, task.CreatedAt}
+	}
+
+	meta := &models.ChangeSetMeta{Note: "Repaired row order"}
+	result := &models.RepairOrderResult{}
+	for _, list := range []struct {
+		table   string
+		links   []orderLink
+		changed *int
+	}{
+		{"resources", resourceLinks, &result.Resources},
+		{"tasks", taskLinks, &result.Tasks},
+	} {
+		order, broken := repairedOrder(list.links)
+		if !broken {
+			continue
+		}
+		changed, err := s.repo.RelinkRows(documentID, list.table, order, userID, meta)
+		if err != nil {
+			return nil, fmt.Errorf("failed to repair order of %s: %w", list.table, err)
+		}
+		*list.changed = changed
+	}
+
+	if result.Version, err = s.repo.GetCurrentVersion(documentID); err != nil {
+		return nil, fmt.Errorf("failed to get current version: %w", err)
+	}
+	return result, nil
+}
+
+type orderLink struct {
+	id        uuid.UUID
+	prevID    *uuid.UUID
+	nextID    *uuid.UUID
+	createdAt time.Time
+}
+
+// repairedOrder returns the row order of a prev/next list and whether the
+// stored links disagree with it
+func repairedOrder(links []orderLink) ([]uuid.UUID, bool) {
+	byID := make(map[uuid.UUID]*orderLink, len(links))
+	for i := range links {
+		byID[links[i].id] = &links[i]
+	}
+
+	next := func(link *orderLink) *orderLink {
+		if link.nextID == nil {
+			return nil
+		}
+		return byID[*link.nextID]
+	}
+
+	// Heads have no existing predecessor; the longest list goes first
+	var heads []*orderLink
+	lengths := make(map[uuid.UUID]int)
+	for i := range links {
+		if links[i].prevID != nil && byID[*links[i].prevID] != nil {
+			continue
+		}
+		head := &links[i]
+		visited := make(map[uuid.UUID]bool)
+		for link := head; link != nil && !visited[link.id]; link = next(link) {
+			visited[link.id] = true
+		}
+		heads = append(heads, head)
+		lengths[head.id] = len(visited)
+	}
+	sort.SliceStable(heads, func(i, j int) bool {
+		if lengths[heads[i].id] != lengths[heads[j].id] {
+			return lengths[heads[i].id] > lengths[heads[j].id]
+		}
+		return heads[i].createdAt.Before(heads[j].createdAt)
+	})
+
+	order := make([]uuid.UUID, 0, len(links))
+	visited := make(map[uuid.UUID]bool, len(links))
+	for _, head := range heads {
+		for link := head; link != nil && !visited[link.id]; link = next(link) {
+			order = append(order, link.id)
+			visited[link.id] = true
+		}
+	}
+
+	// Rows only reachable through a cycle
+	var rest []*orderLink
+	for i := range links {
+		if !visited[links[i].id] {
+			rest = append(rest, &links[i])
+		}
+	}
+	sort.SliceStable(rest, func(i, j int) bool { return rest[i].createdAt.Before(rest[j].createdAt) })
+	for _, link := range rest {
+		order = append(order, link.id)
+	}
+
+	for i, id := range order {
+		link := byID[id]
+		var prevID, nextID *uuid.UUID
+		if i > 0 {
+			prevID = &order[i-1]
+		}
+		if i < len(order)-1 {
+			nextID = &order[i+1]
+		}
+		if !equalID(link.prevID, prevID) || !equalID(link.nextID, nextID) {
+			return order, true
+		}
+	}
+	return order, false
+}
+
+func equalID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// sprintsOf returns the sprint codes of a task, nil when unset
+func sprintsOf(task *models.Task) []string {
+	if task.SprintsAuto == nil {
+		return nil
+	}
+	return *task.SprintsAuto
+}
@@ -0,0 +1,434 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"roadmap/internal/models"
+)
+
+// applyUpdateRequest applies an UpdateRequest to an in-memory copy of the data
+// with the same semantics as Repository.UpdateData: fields that are set
+// overwrite stored values, unknown ids create new rows, deletions remove rows.
+// Rows are reordered by their linked lists afterwards.
+func applyUpdateRequest(data *models.DataResponse, req *models.UpdateRequest) error {
+	for _, team := range req.Teams {
+		i := indexOf(len(data.Teams), func(i int) bool { return data.Teams[i].ID == team.ID })
+		if i < 0 {
+			data.Teams = append(data.Teams, models.Team{ID: team.ID})
+			i = len(data.Teams) - 1
+		}
+		if err := overlayJSON(&data.Teams[i], team); err != nil {
+			return fmt.Errorf("failed to apply team %s: %w", team.ID, err)
+		}
+	}
+
+	for _, sprint := range req.Sprints {
+		i := indexOf(len(data.Sprints), func(i int) bool { return data.Sprints[i].ID == sprint.ID })
+		if i < 0 {
+			data.Sprints = append(data.Sprints, models.Sprint{ID: sprint.ID})
+			i = len(data.Sprints) - 1
+		}
+		if err := overlayJSON(&data.Sprints[i], sprint); err != nil {
+			return fmt.Errorf("failed to apply sprint %s: %w", sprint.ID, err)
+		}
+	}
+
+	for _, resource := range req.Resources {
+		i := indexOf(len(data.Resources), func(i int) bool { return data.Resources[i].ID == resource.ID })
+		if i < 0 {
+			data.Resources = append(data.Resources, models.Resource{ID: resource.ID, Kind: models.RowKindResource})
+			i = len(data.Resources) - 1
+		}
+		if err := overlayJSON(&data.Resources[i], resource); err != nil {
+			return fmt.Errorf("failed to apply resource %s: %w", resource.ID, err)
+		}
+	}
+
+	for _, task := range req.Tasks {
+		i := indexOf(len(data.Tasks), func(i int) bool { return data.Tasks[i].ID == task.ID })
+		if i < 0 {
+			data.Tasks = append(data.Tasks, models.Task{ID: task.ID, Kind: models.RowKindTask})
+			i = len(data.Tasks) - 1
+		}
+		if err := overlayJSON(&data.Tasks[i], task); err != nil {
+			return fmt.Errorf("failed to apply task %s: %w", task.ID, err)
+		}
+	}
+
+	for tableName, ids := range req.Deleted {
+		deleted := make(map[uuid.UUID]bool, len(ids))
+		for _, id := range ids {
+			deleted[id] = true
+		}
+		switch tableName {
+		case "teams":
+			data.Teams = filterSlice(data.Teams, func(t models.Team) bool { return !deleted[t.ID] })
+		case "sprints":
+			data.Sprints = filterSlice(data.Sprints, func(s models.Sprint) bool { return !deleted[s.ID] })
+		case "resources":
+			data.Resources = filterSlice(data.Resources, func(r models.Resource) bool { return !deleted[r.ID] })
+			for i := range data.Resources {
+				clearDeletedLinks(&data.Resources[i].PrevID, &data.Resources[i].NextID, deleted)
+			}
+		case "tasks":
+			data.Tasks = filterSlice(data.Tasks, func(t models.Task) bool { return !deleted[t.ID] })
+			for i := range data.Tasks {
+				clearDeletedLinks(&data.Tasks[i].PrevID, &data.Tasks[i].NextID, deleted)
+			}
+		default:
+			return fmt.Errorf("unknown table for deletion: %s", tableName)
+		}
+	}
+
+	refreshDisplayNames(data)
+	data.Resources = orderByLinks(data.Resources, func(r models.Resource) (uuid.UUID, *uuid.UUID, *uuid.UUID) {
+		return r.ID, r.PrevID, r.NextID
+	})
+	data.Tasks = orderByLinks(data.Tasks, func(t models.Task) (uuid.UUID, *uuid.UUID, *uuid.UUID) {
+		return t.ID, t.PrevID, t.NextID
+	})
+	return nil
+}
+
+// mergeUpdateRequests folds patch into base so that applying the result equals
+// applying base and then patch. Deleting a row drops its pending update;
+// upserting a row deleted by base drops the deletion, as UpdateData deletes
+// after upserting.
+func mergeUpdateRequests(base *models.UpdateRequest, patch *models.UpdateRequest) error {
+	for _, team := range patch.Teams {
+		i := indexOf(len(base.Teams), func(i int) bool { return base.Teams[i].ID == team.ID })
+		if i < 0 {
+			base.Teams = append(base.Teams, team)
+		} else if err := overlayJSON(&base.Teams[i], team); err != nil {
+			return err
+		}
+	}
+	for _, sprint := range patch.Sprints {
+		i := indexOf(len(base.Sprints), func(i int) bool { return base.Sprints[i].ID == sprint.ID })
+		if i < 0 {
+			base.Sprints = append(base.Sprints, sprint)
+		} else if err := overlayJSON(&base.Sprints[i], sprint); err != nil {
+			return err
+		}
+	}
+	for _, resource := range patch.Resources {
+		i := indexOf(len(base.Resources), func(i int) bool { return base.Resources[i].ID == resource.ID })
+		if i < 0 {
+			base.Resources = append(base.Resources, resource)
+		} else if err := overlayJSON(&base.Resources[i], resource); err != nil {
+			return err
+		}
+	}
+	for _, task := range patch.Tasks {
+		i := indexOf(len(base.Tasks), func(i int) bool { return base.Tasks[i].ID == task.ID })
+		if i < 0 {
+			base.Tasks = append(base.Tasks, task)
+		} else if err := overlayJSON(&base.Tasks[i], task); err != nil {
+			return err
+		}
+	}
+
+	upserted := updateRequestRecords(&models.UpdateRequest{
+		Teams: patch.Teams, Sprints: patch.Sprints, Resources: patch.Resources, Tasks: patch.Tasks,
+	})
+	for tableName, ids := range base.Deleted {
+		base.Deleted[tableName] = filterSlice(ids, func(id uuid.UUID) bool { return !upserted[tableName][id] })
+		if len(base.Deleted[tableName]) == 0 {
+			delete(base.Deleted, tableName)
+		}
+	}
+
+	for tableName, ids := range patch.Deleted {
+		deleted := make(map[uuid.UUID]bool, len(ids))
+		for _, id := range ids {
+			deleted[id] = true
+		}
+		switch tableName {
+		case "teams":
+			base.Teams = filterSlice(base.Teams, func(t models.Team) bool { return !deleted[t.ID] })
+		case "sprints":
+			base.Sprints = filterSlice(base.Sprints, func(s models.Sprint) bool { return !deleted[s.ID] })
+		case "resources":
+			base.Resources = filterSlice(base.Resources, func(r models.ResourceUpdate) bool { return !deleted[r.ID] })
+		case "tasks":
+			base.Tasks = filterSlice(base.Tasks, func(t models.TaskUpdate) bool { return !deleted[t.ID] })
+		default:
+			return fmt.Errorf("unknown table for deletion: %s", tableName)
+		}
+		if base.Deleted == nil {
+			base.Deleted = make(map[string][]uuid.UUID)
+		}
+		for _, id := range ids {
+			if !containsID(base.Deleted[tableName], id) {
+				base.Deleted[tableName] = append(base.Deleted[tableName], id)
+			}
+		}
+	}
+	return nil
+}
+
+// updateRequestRecords returns the records touched by an UpdateRequest as table -> ids
+func updateRequestRecords(req *models.UpdateRequest) map[string]map[uuid.UUID]bool {
+	records := map[string]map[uuid.UUID]bool{
+		"teams": {}, "sprints": {}, "resources": {}, "tasks": {},
+	}
+	for _, team := range req.Teams {
+		records["teams"][team.ID] = true
+	}
+	for _, sprint := range req.Sprints {
+		records["sprints"][sprint.ID] = true
+	}
+	for _, resource := range req.Resources {
+		records["resources"][resource.ID] = true
+	}
+	for _, task := range req.Tasks {
+		records["tasks"][task.ID] = true
+	}
+	for tableName, ids := range req.Deleted {
+		if records[tableName] == nil {
+			records[tableName] = map[uuid.UUID]bool{}
+		}
+		for _, id := range ids {
+			records[tableName][id] = true
+		}
+	}
+	return records
+}
+
+// ignoredDiffFields are bookkeeping or display-only fields left out of comparisons
+var ignoredDiffFields = map[string]bool{
+	"id": true, "kind": true, "createdAt": true, "updatedAt": true, "team": true,
+}
+
+// diffData compares two snapshots of a document and returns the net change of
+// every record that differs, with per-field old and new values
+func diffData(before, after *models.DataResponse) ([]models.RecordChange, error) {
+	var changes []models.RecordChange
+	tables := []struct {
+		name          string
+		before, after interface{}
+	}{
+		{"teams", before.Teams, after.Teams},
+		{"sprints", before.Sprints, after.Sprints},
+		{"resources", before.Resources, after.Resources},
+		{"tasks", before.Tasks, after.Tasks},
+	}
+	for _, table := range tables {
+		beforeRows, order, err := rowsByID(table.before)
+		if err != nil {
+			return nil, err
+		}
+		afterRows, afterOrder, err := rowsByID(table.after)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range order {
+			if _, ok := afterRows[id]; !ok {
+				changes = append(changes, models.RecordChange{
+					Table: table.name, RecordID: id, Operation: "DELETE",
+					Fields: diffFields(beforeRows[id], nil),
+				})
+			}
+		}
+		for _, id := range afterOrder {
+			oldRow, existed := beforeRows[id]
+			fields := diffFields(oldRow, afterRows[id])
+			if !existed {
+				changes = append(changes, models.RecordChange{
+					Table: table.name, RecordID: id, Operation: "INSERT", Fields: fields,
+				})
+			} else if len(fields) > 0 {
+				changes = append(changes, models.RecordChange{
+					Table: table.name, RecordID: id, Operation: "UPDATE", Fields: fields,
+				})
+			}
+		}
+	}
+	return changes, nil
+}
+
+// diffFields returns fields whose values differ between two JSON objects
+func diffFields(before, after map[string]interface{}) map[string]models.FieldChange {
+	fields := make(map[string]models.FieldChange)
+	for key, oldValue := range before {
+		if ignoredDiffFields[key] {
+			continue
+		}
+		newValue := after[key]
+		if !reflect.DeepEqual(oldValue, newValue) {
+			fields[key] = models.FieldChange{Old: oldValue, New: newValue}
+		}
+	}
+	for key, newValue := range after {
+		if ignoredDiffFields[key] {
+			continue
+		}
+		if _, seen := before[key]; !seen && newValue != nil {
+			fields[key] = models.FieldChange{Old: nil, New: newValue}
+		}
+	}
+	return fields
+}
+
+// rowsByID converts a slice of models into JSON objects keyed by id, keeping order
+func rowsByID(rows interface{}) (map[uuid.UUID]map[string]interface{}, []uuid.UUID, error) {
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	var objects []map[string]interface{}
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[uuid.UUID]map[string]interface{}, len(objects))
+	order := make([]uuid.UUID, 0, len(objects))
+	for _, object := range objects {
+		idStr, _ := object["id"].(string)
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			continue
+		}
+		byID[id] = object
+		order = append(order, id)
+	}
+	return byID, order, nil
+}
+
+// overlayJSON copies every field present in the JSON encoding of patch onto dst.
+// Update structs omit unset fields, so only set fields are overwritten.
+func overlayJSON(dst interface{}, patch interface{}) error {
+	dstData, err := json.Marshal(dst)
+	if err != nil {
+		return err
+	}
+	patchData, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	var merged, patchFields map[string]json.RawMessage
+	if err := json.Unmarshal(dstData, &merged); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(patchData, &patchFields); err != nil {
+		return err
+	}
+	for key, value := range patchFields {
+		// Update structs reuse models with non-pointer timestamps; never copy them
+		if key == "createdAt" || key == "updatedAt" {
+			continue
+		}
+		merged[key] = value
+	}
+
+	mergedData, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	// Reset dst so fields cleared in the merged object do not survive
+	reflect.ValueOf(dst).Elem().Set(reflect.Zero(reflect.TypeOf(dst).Elem()))
+	return json.Unmarshal(mergedData, dst)
+}
+
+// refreshDisplayNames recomputes team names shown for tasks and resources
+func refreshDisplayNames(data *models.DataResponse) {
+	teamNames := make(map[string]string, len(data.Teams))
+	for _, team := range data.Teams {
+		if team.Name != nil {
+			teamNames[team.ID.String()] = *team.Name
+		}
+	}
+	for i := range data.Tasks {
+		data.Tasks[i].Kind = models.RowKindTask
+		data.Tasks[i].Team = ""
+		if data.Tasks[i].TeamID != nil {
+			data.Tasks[i].Team = teamNames[data.Tasks[i].TeamID.String()]
+		}
+	}
+	for i := range data.Resources {
+		data.Resources[i].Kind = models.RowKindResource
+		if data.Resources[i].TeamUUIDs != nil {
+			names := make(pq.StringArray, len(data.Resources[i].TeamUUIDs))
+			for j, id := range data.Resources[i].TeamUUIDs {
+				names[j] = teamNames[id]
+			}
+			data.Resources[i].TeamIDs = &names
+		}
+	}
+}
+
+// orderByLinks orders rows following prev/next pointers like the repository
+// does, appending rows not reachable from the head at the end
+func orderByLinks[T any](rows []T, links func(T) (uuid.UUID, *uuid.UUID, *uuid.UUID)) []T {
+	byID := make(map[uuid.UUID]T, len(rows))
+	var head *uuid.UUID
+	for _, row := range rows {
+		id, prevID, _ := links(row)
+		byID[id] = row
+		if prevID == nil && head == nil {
+			head = &id
+		}
+	}
+
+	ordered := make([]T, 0, len(rows))
+	visited := make(map[uuid.UUID]bool, len(rows))
+	for current := head; current != nil && !visited[*current]; {
+		row, ok := byID[*current]
+		if !ok {
+			break
+		}
+		ordered = append(ordered, row)
+		visited[*current] = true
+		_, _, current = links(row)
+	}
+	for _, row := range rows {
+		if id, _, _ := links(row); !visited[id] {
+			ordered = append(ordered, row)
+		}
+	}
+	return ordered
+}
+
+func clearDeletedLinks(prevID, nextID **uuid.UUID, deleted map[uuid.UUID]bool) {
+	if *prevID != nil && deleted[**prevID] {
+		*prevID = nil
+	}
+	if *nextID != nil && deleted[**nextID] {
+		*nextID = nil
+	}
+}
+
+func indexOf(n int, match func(int) bool) int {
+	for i := 0; i < n; i++ {
+		if match(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+func filterSlice[T any](items []T, keep func(T) bool) []T {
+	result := items[:0]
+	for _, item := range items {
+		if keep(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, existing := range ids {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+	"roadmap/internal/repository"
+)
+
+// ErrScenarioClosed is returned when a merged or discarded scenario is changed
+var ErrScenarioClosed = errors.New("scenario is not open")
+
+// GetScenarios returns all scenarios of the document
+func (s *Service) GetScenarios(documentID uuid.UUID) ([]models.Scenario, error) {
+	scenarios, err := s.repo.GetScenarios(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scenarios: %w", err)
+	}
+	return scenarios, nil
+}
+
+// GetScenario returns a scenario of the document or ErrNotFound
+func (s *Service) GetScenario(documentID, id uuid.UUID) (*models.Scenario, error) {
+	scenario, err := s.repo.GetScenario(documentID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("scenario %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scenario %s: %w", id, err)
+	}
+	return scenario, nil
+}
+
+// CreateScenario forks the current plan into an empty named scenario; it
+// requires the planner role
+func (s *Service) CreateScenario(documentID uuid.UUID, req *models.CreateScenarioRequest) (*models.Scenario, error) {
+	if err := s.requireRole(documentID, req.UserID, models.RolePlanner); err != nil {
+		return nil, err
+	}
+
+	version, err := s.repo.GetCurrentVersion(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current version: %w", err)
+	}
+
+	scenario, err := s.repo.CreateScenario(documentID, req.Name, version, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scenario: %w", err)
+	}
+	return scenario, nil
+}
+
+// GetScenarioData returns the main plan with the scenario overrides applied.
+// The returned version is the scenario's own version used for UpdateScenario.
+func (s *Service) GetScenarioData(documentID, id uuid.UUID) (*models.DataResponse, error) {
+	scenario, err := s.GetScenario(documentID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.GetAllData(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all data: %w", err)
+	}
+	if err := applyUpdateRequest(data, &scenario.Changes); err != nil {
+		return nil, fmt.Errorf("failed to apply scenario %s: %w", id, err)
+	}
+	data.Version = scenario.Version
+
+	return data, nil
+}
+
+// UpdateScenario records changes in the scenario without touching the main plan.
+// req.Version must match the scenario version (optimistic locking). It
+// requires the planner role; merging checks the roles for every change.
+func (s *Service) UpdateScenario(documentID, id uuid.UUID, req *models.UpdateRequest) (*models.UpdateResponse, error) {
+	if err := s.requireRole(documentID, req.UserID, models.RolePlanner); err != nil {
+		return nil, err
+	}
+	scenario, err := s.GetScenario(documentID, id)
+	if err != nil {
+		return nil, err
+	}
+	if scenario.Status != models.ScenarioStatusOpen {
+		return &models.UpdateResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Scenario is %s", scenario.Status),
+		}, nil
+	}
+	if req.Version != scenario.Version {
+		return &models.UpdateResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Version conflict: client version %d, scenario version %d", req.Version, scenario.Version),
+		}, nil
+	}
+
+	changes := scenario.Changes
+	if err := mergeUpdateRequests(&changes, req); err != nil {
+		return &models.UpdateResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Failed to merge changes: %v", err),
+		}, nil
+	}
+
+	newVersion, err := s.repo.UpdateScenarioChanges(id, scenario.Version, &changes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &models.UpdateResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Version conflict: scenario %s was changed or closed concurrently", id),
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update scenario %s: %w", id, err)
+	}
+
+	return &models.UpdateResponse{
+		Version: newVersion,
+		Success: true,
+	}, nil
+}
+
+// CompareScenario returns the net per-record difference between the current
+// main plan and the plan as seen through the scenario
+func (s *Service) CompareScenario(documentID, id uuid.UUID) (*models.ScenarioComparison, error) {
+	scenario, err := s.GetScenario(documentID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	mainData, err := s.repo.GetAllData(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all data: %w", err)
+	}
+	scenarioData, err := s.GetScenarioData(documentID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := diffData(mainData, scenarioData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare scenario %s: %w", id, err)
+	}
+	if changes == nil {
+		changes = []models.RecordChange{}
+	}
+
+	return &models.ScenarioComparison{
+		ScenarioID:  scenario.ID,
+		BaseVersion: scenario.BaseVersion,
+		MainVersion: mainData.Version,
+		Changes:     changes,
+	}, nil
+}
+
+// MergeScenario applies the scenario change set to the main plan through
+// UpdateData. Unless forced, it refuses to overwrite records that were changed
+// in main after the scenario was forked.
+func (s *Service) MergeScenario(documentID, id uuid.UUID, req *models.MergeScenarioRequest) (*models.UpdateResponse, error) {
+	scenario, err := s.GetScenario(documentID, id)
+	if err != nil {
+		return nil, err
+	}
+	if scenario.Status != models.ScenarioStatusOpen {
+		return &models.UpdateResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Scenario is %s", scenario.Status),
+		}, nil
+	}
+
+	if !req.Force {
+		mainChanges, _, err := s.repo.GetChangesSince(documentID, scenario.BaseVersion, 0)
+		if errors.Is(err, repository.ErrHistoryPruned) {
+			return &models.UpdateResponse{
+				Success: false,
+				Error: fmt.Sprintf("Version conflict: changes since version %d were compacted, merge with force",
+					scenario.BaseVersion),
+			}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get changes since version %d: %w", scenario.BaseVersion, err)
+		}
+		touched := updateRequestRecords(&scenario.Changes)
+		conflicts := make(map[string]bool)
+		for _, change := range mainChanges {
+			if touched[change.TableName][change.RecordID] {
+				conflicts[change.TableName+"/"+change.RecordID.String()] = true
+			}
+		}
+		if len(conflicts) > 0 {
+			records := make([]string, 0, len(conflicts))
+			for record := range conflicts {
+				records = append(records, record)
+			}
+			sort.Strings(records)
+			return &models.UpdateResponse{
+				Success: false,
+				Error: fmt.Sprintf("Version conflict: records changed in main since version %d: %s",
+					scenario.BaseVersion, strings.Join(records, ", ")),
+			}, nil
+		}
+	}
+
+	update := scenario.Changes
+	update.Version = req.Version
+	update.UserID = req.UserID
+	update.Meta = req.Meta
+
+	// The scenario is closed in the transaction of the update, so that it is
+	// merged exactly once
+	response, err := s.updateData(documentID, &update, func(tx *sql.Tx) error {
+		return s.repo.MarkScenarioMerged(tx, id)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return &models.UpdateResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Scenario %s was closed concurrently", id),
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge scenario %s: %w", id, err)
+	}
+	return response, nil
+}
+
+// DiscardScenario closes the scenario without applying it; it requires the
+// planner role
+func (s *Service) DiscardScenario(documentID uuid.UUID, actorID string, id uuid.UUID) error {
+	if err := s.requireRole(documentID, actorID, models.RolePlanner); err != nil {
+		return err
+	}
+	scenario, err := s.GetScenario(documentID, id)
+	if err != nil {
+		return err
+	}
+	if scenario.Status != models.ScenarioStatusOpen {
+		return fmt.Errorf("scenario %s is %s: %w", id, scenario.Status, ErrScenarioClosed)
+	}
+	err = s.repo.SetScenarioStatus(id, models.ScenarioStatusDiscarded)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("scenario %s was closed concurrently: %w", id, ErrScenarioClosed)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to discard scenario %s: %w", id, err)
+	}
+	return nil
+}
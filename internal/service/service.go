@@ -1,14 +1,25 @@
 package service
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 
+	"github.com/google/uuid"
+
 	"roadmap/internal/models"
 	"roadmap/internal/repository"
 )
 
+// ErrNotFound is returned when a requested record does not exist
+var ErrNotFound = errors.New("not found")
+
 type Service struct {
 	repo *repository.Repository
+
+	// accessControl enables role checks, see EnableAccessControl
+	accessControl bool
+	admins        map[string]bool
 }
 
 func New(repo *repository.Repository) *Service {
@@ -16,8 +27,8 @@ func New(repo *repository.Repository) *Service {
 }
 
 // GetCurrentVersion returns the current document version
-func (s *Service) GetCurrentVersion() (*models.VersionResponse, error) {
-	version, err := s.repo.GetCurrentVersion()
+func (s *Service) GetCurrentVersion(documentID uuid.UUID) (*models.VersionResponse, error) {
+	version, err := s.repo.GetCurrentVersion(documentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current version: %w", err)
 	}
@@ -26,8 +37,8 @@ func (s *Service) GetCurrentVersion() (*models.VersionResponse, error) {
 }
 
 // GetAllData returns all data with the current version
-func (s *Service) GetAllData() (*models.DataResponse, error) {
-	data, err := s.repo.GetAllData()
+func (s *Service) GetAllData(documentID uuid.UUID) (*models.DataResponse, error) {
+	data, err := s.repo.GetAllData(documentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all data: %w", err)
 	}
@@ -35,26 +46,78 @@ func (s *Service) GetAllData() (*models.DataResponse, error) {
 	return data, nil
 }
 
-// GetDataDiff returns changes since the specified version
-func (s *Service) GetDataDiff(fromVersion int64) (*models.DiffResponse, error) {
-	currentVersion, err := s.repo.GetCurrentVersion()
+// GetDataDiff returns a page of changes after fromVersion, or after the
+// cursor when continuing a paginated diff. On the first page it asks the client
+// to fetch full data instead when the diff would be larger than the document.
+func (s *Service) GetDataDiff(documentID uuid.UUID, fromVersion int64, cursor *int64, limit int) (*models.DiffResponse, error) {
+	currentVersion, err := s.repo.GetCurrentVersion(documentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current version: %w", err)
 	}
 
-	changes, err := s.repo.GetChangesSince(fromVersion)
+	after := fromVersion
+	if cursor != nil {
+		after = *cursor
+	} else if fromVersion < currentVersion {
+		// Every change row carries a whole record, so more changes than rows
+		// means the full data response is smaller
+		changeCount, rowCount, err := s.repo.GetDiffSize(documentID, fromVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate diff size: %w", err)
+		}
+		if changeCount > rowCount {
+			return &models.DiffResponse{
+				Version:       currentVersion,
+				Changes:       []models.ChangeLog{},
+				FetchFullData: true,
+			}, nil
+		}
+	}
+
+	changes, hasMore, err := s.repo.GetChangesSince(documentID, after, limit)
+	if errors.Is(err, repository.ErrHistoryPruned) {
+		// The client is too far behind the retained log and has to reload
+		return &models.DiffResponse{
+			Version:        currentVersion,
+			Changes:        []models.ChangeLog{},
+			ResyncRequired: true,
+		}, nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get changes since version %d: %w", fromVersion, err)
+		return nil, fmt.Errorf("failed to get changes since version %d: %w", after, err)
 	}
 
-	return &models.DiffResponse{
+	response := &models.DiffResponse{
 		Version: currentVersion,
 		Changes: changes,
-	}, nil
+		HasMore: hasMore,
+	}
+	if changes == nil {
+		response.Changes = []models.ChangeLog{}
+	}
+	if hasMore {
+		nextCursor := changes[len(changes)-1].VersionNumber
+		response.NextCursor = &nextCursor
+	}
+
+	authors := make([]*string, len(changes))
+	for i := range changes {
+		authors[i] = changes[i].UserID
+	}
+	if response.Users, err = s.userDirectory(authors); err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
 // UpdateData updates data in the database with optimistic locking
-func (s *Service) UpdateData(req *models.UpdateRequest) (*models.UpdateResponse, error) {
+func (s *Service) UpdateData(documentID uuid.UUID, req *models.UpdateRequest) (*models.UpdateResponse, error) {
+	return s.updateData(documentID, req, nil)
+}
+
+// updateData is UpdateData running beforeCommit, when set, in the transaction
+// of the update; an error of beforeCommit rolls the update back and is returned
+func (s *Service) updateData(documentID uuid.UUID, req *models.UpdateRequest, beforeCommit func(tx *sql.Tx) error) (*models.UpdateResponse, error) {
 	fmt.Printf("Service: UpdateData called with %d tasks\n", len(req.Tasks))
 
 	// Start transaction
@@ -69,7 +132,7 @@ func (s *Service) UpdateData(req *models.UpdateRequest) (*models.UpdateResponse,
 	defer tx.Rollback() // Will be ignored if tx.Commit() succeeds
 
 	// Check current version for optimistic locking
-	currentVersion, err := s.repo.GetCurrentVersion()
+	currentVersion, err := s.repo.GetCurrentVersion(documentID)
 	if err != nil {
 		fmt.Printf("Service: Failed to get current version: %v\n", err)
 		return &models.UpdateResponse{
@@ -87,9 +150,24 @@ func (s *Service) UpdateData(req *models.UpdateRequest) (*models.UpdateResponse,
 		}, nil
 	}
 
+	// Check roles of the user for every changed item
+	if s.accessControl {
+		forbidden, err := s.forbiddenChanges(documentID, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(forbidden) > 0 {
+			return &models.UpdateResponse{
+				Success:   false,
+				Error:     fmt.Sprintf("Permission denied: %d items require a role the user does not have", len(forbidden)),
+				Forbidden: forbidden,
+			}, nil
+		}
+	}
+
 	// Update data
 	fmt.Printf("Service: Calling repository UpdateData\n")
-	err = s.repo.UpdateData(tx, req)
+	err = s.repo.UpdateData(tx, documentID, req)
 	if err != nil {
 		fmt.Printf("Service: Repository UpdateData failed: %v\n", err)
 		return &models.UpdateResponse{
@@ -99,6 +177,12 @@ func (s *Service) UpdateData(req *models.UpdateRequest) (*models.UpdateResponse,
 	}
 	fmt.Printf("Service: Repository UpdateData succeeded\n")
 
+	if beforeCommit != nil {
+		if err := beforeCommit(tx); err != nil {
+			return nil, err
+		}
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		return &models.UpdateResponse{
@@ -108,7 +192,7 @@ func (s *Service) UpdateData(req *models.UpdateRequest) (*models.UpdateResponse,
 	}
 
 	// Get new version after commit
-	newVersion, err := s.repo.GetCurrentVersion()
+	newVersion, err := s.repo.GetCurrentVersion(documentID)
 	if err != nil {
 		return &models.UpdateResponse{
 			Success: false,
@@ -121,3 +205,55 @@ func (s *Service) UpdateData(req *models.UpdateRequest) (*models.UpdateResponse,
 		Success: true,
 	}, nil
 }
+
+// GetDefaultDocumentID returns the document addressed by the unscoped API routes
+func (s *Service) GetDefaultDocumentID() (uuid.UUID, error) {
+	id, err := s.repo.GetDefaultDocumentID()
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get default document: %w", err)
+	}
+	return id, nil
+}
+
+// GetRoadmaps returns the roadmap documents; with access control only those
+// the user has a role in
+func (s *Service) GetRoadmaps(userID string) ([]models.Roadmap, error) {
+	member := ""
+	if s.accessControl && !s.admins[userID] {
+		member = userID
+	}
+	roadmaps, err := s.repo.GetRoadmaps(member)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roadmaps: %w", err)
+	}
+	return roadmaps, nil
+}
+
+// GetRoadmap returns a roadmap document or ErrNotFound
+func (s *Service) GetRoadmap(id uuid.UUID) (*models.Roadmap, error) {
+	roadmap, err := s.repo.GetRoadmap(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("roadmap %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roadmap %s: %w", id, err)
+	}
+	return roadmap, nil
+}
+
+// CreateRoadmap creates a new empty roadmap document. With access control the
+// creator becomes its admin.
+func (s *Service) CreateRoadmap(actorID string, req *models.CreateRoadmapRequest) (*models.Roadmap, error) {
+	admin := ""
+	if s.accessControl {
+		if actorID == "" {
+			return nil, fmt.Errorf("creating a roadmap requires an authenticated user: %w", ErrForbidden)
+		}
+		admin = actorID
+	}
+	roadmap, err := s.repo.CreateRoadmap(req.Name, admin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create roadmap: %w", err)
+	}
+	return roadmap, nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+	"roadmap/internal/planning"
+)
+
+// ErrInvalidPatch is returned when a simulated patch cannot be applied
+var ErrInvalidPatch = errors.New("invalid patch")
+
+// Simulate applies the patch to an in-memory copy of the plan, re-runs
+// auto-planning and returns task placements and resource load before and after.
+// Nothing is written to the database or the change log.
+func (s *Service) Simulate(documentID uuid.UUID, req *models.UpdateRequest) (*models.SimulationResponse, error) {
+	data, err := s.repo.GetAllData(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all data: %w", err)
+	}
+
+	before := planning.Compute(data)
+	beforeResources := append([]models.Resource(nil), data.Resources...)
+
+	if err := applyUpdateRequest(data, req); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	after := planning.Compute(data)
+
+	weeks := after.Calendar.Weeks
+	if before.Calendar.Weeks > weeks {
+		weeks = before.Calendar.Weeks
+	}
+
+	return &models.SimulationResponse{
+		Version:   data.Version,
+		Weeks:     after.Calendar.Weeks,
+		Tasks:     simulatedTasks(before, after),
+		Resources: simulatedResources(before, after, beforeResources, data.Resources, weeks),
+	}, nil
+}
+
+func simulatedTasks(before, after *planning.Result) []models.SimulatedTask {
+	beforeByID := make(map[uuid.UUID]*models.Task, len(before.Tasks))
+	for i := range before.Tasks {
+		beforeByID[before.Tasks[i].ID] = &before.Tasks[i]
+	}
+
+	tasks := make([]models.SimulatedTask, 0, len(after.Tasks))
+	seen := make(map[uuid.UUID]bool, len(after.Tasks))
+	for i := range after.Tasks {
+		task := &after.Tasks[i]
+		seen[task.ID] = true
+		tasks = append(tasks, simulatedTask(task, beforeByID[task.ID], task))
+	}
+	for i := range before.Tasks {
+		if task := &before.Tasks[i]; !seen[task.ID] {
+			tasks = append(tasks, simulatedTask(task, task, nil))
+		}
+	}
+	return tasks
+}
+
+func simulatedTask(task, before, after *models.Task) models.SimulatedTask {
+	simulated := models.SimulatedTask{
+		ID:     task.ID,
+		Task:   task.TaskName,
+		Epic:   task.Epic,
+		Before: placement(before),
+		After:  placement(after),
+	}
+	if simulated.Before != nil && simulated.After != nil &&
+		simulated.Before.EndWeek != nil && simulated.After.EndWeek != nil {
+		shift := *simulated.After.EndWeek - *simulated.Before.EndWeek
+		simulated.EndShift = &shift
+	}
+	simulated.Changed = simulated.Before == nil || simulated.After == nil ||
+		!equalWeek(simulated.Before.StartWeek, simulated.After.StartWeek) ||
+		!equalWeek(simulated.Before.EndWeek, simulated.After.EndWeek) ||
+		!equalFloats(simulated.Before.Weeks, simulated.After.Weeks)
+	return simulated
+}
+
+func placement(task *models.Task) *models.TaskPlacement {
+	if task == nil {
+		return nil
+	}
+	result := &models.TaskPlacement{
+		StartWeek:   task.StartWeek,
+		EndWeek:     task.EndWeek,
+		SprintsAuto: []string{},
+		Weeks:       []float64{},
+	}
+	if task.Fact != nil {
+		result.Fact = *task.Fact
+	}
+	if task.SprintsAuto != nil {
+		result.SprintsAuto = *task.SprintsAuto
+	}
+	if task.Weeks != nil {
+		result.Weeks = *task.Weeks
+	}
+	return result
+}
+
+func simulatedResources(before, after *planning.Result, beforeResources, afterResources []models.Resource, weeks int) []models.SimulatedResource {
+	resources := make([]models.SimulatedResource, 0, len(afterResources))
+	seen := make(map[uuid.UUID]bool, len(afterResources))
+	add := func(resource models.Resource) {
+		seen[resource.ID] = true
+		simulated := models.SimulatedResource{
+			ID:             resource.ID,
+			Function:       resource.Function,
+			Employee:       resource.Employee,
+			CapacityBefore: padWeeks(before.Capacity[resource.ID], weeks),
+			CapacityAfter:  padWeeks(after.Capacity[resource.ID], weeks),
+			LoadBefore:     padWeeks(before.Load[resource.ID], weeks),
+			LoadAfter:      padWeeks(after.Load[resource.ID], weeks),
+			FreeDelta:      make([]float64, weeks),
+		}
+		for w := 0; w < weeks; w++ {
+			freeBefore := simulated.CapacityBefore[w] - simulated.LoadBefore[w]
+			freeAfter := simulated.CapacityAfter[w] - simulated.LoadAfter[w]
+			simulated.FreeDelta[w] = freeAfter - freeBefore
+			if simulated.FreeDelta[w] != 0 || simulated.LoadBefore[w] != simulated.LoadAfter[w] {
+				simulated.Changed = true
+			}
+		}
+		resources = append(resources, simulated)
+	}
+
+	for _, resource := range afterResources {
+		add(resource)
+	}
+	for _, resource := range beforeResources {
+		if !seen[resource.ID] {
+			add(resource)
+		}
+	}
+	return resources
+}
+
+// padWeeks returns a copy of values extended with zeros to the given length
+func padWeeks(values []float64, weeks int) []float64 {
+	padded := make([]float64, weeks)
+	copy(padded, values)
+	return padded
+}
+
+func equalWeek(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalFloats(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
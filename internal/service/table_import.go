@@ -0,0 +1,867 @@
+package service
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"roadmap/internal/models"
+	"roadmap/internal/planning"
+)
+
+// Fields a column of an imported table can be mapped to. Week columns map to
+// "#N" with the 1-based week number.
+const (
+	importFieldType      = "type"
+	importFieldID        = "id"
+	importFieldStatus    = "status"
+	importFieldEpic      = "epic"
+	importFieldTask      = "task"
+	importFieldTeam      = "team"
+	importFieldFn        = "fn"
+	importFieldEmpl      = "empl"
+	importFieldPlanEmpl  = "planEmpl"
+	importFieldPlanWeeks = "planWeeks"
+	importFieldBlockers  = "blockers"
+	importFieldAuto      = "autoPlanEnabled"
+)
+
+var importFields = map[string]bool{
+	importFieldType: true, importFieldID: true, importFieldStatus: true, importFieldEpic: true,
+	importFieldTask: true, importFieldTeam: true, importFieldFn: true, importFieldEmpl: true,
+	importFieldPlanEmpl: true, importFieldPlanWeeks: true, importFieldBlockers: true, importFieldAuto: true,
+}
+
+// importHeaders maps lower-case column headers, among them those of the CSV
+// export, to fields. Read-only columns such as Fact are not imported.
+var importHeaders = map[string]string{
+	"type": importFieldType, "тип": importFieldType,
+	"id":        importFieldID,
+	"status":    importFieldStatus,
+	"epic":      importFieldEpic,
+	"task":      importFieldTask,
+	"team":      importFieldTeam,
+	"fn":        importFieldFn,
+	"empl":      importFieldEmpl,
+	"plan empl": importFieldPlanEmpl, "planempl": importFieldPlanEmpl,
+	"plan weeks": importFieldPlanWeeks, "planweeks": importFieldPlanWeeks,
+	"blocker": importFieldBlockers, "blockers": importFieldBlockers, "blocker names": importFieldBlockers,
+	"auto": importFieldAuto, "autoplanenabled": importFieldAuto,
+}
+
+var (
+	weekHeaderPattern = regexp.MustCompile(`^#(\d+)(\s|$)`)
+	weekFieldPattern  = regexp.MustCompile(`^#(\d+)$`)
+	rowRefPattern     = regexp.MustCompile(`^#?(\d+)$`)
+)
+
+// ImportTable reads rows pasted from a spreadsheet and matches them against
+// the plan: tasks by id or by name (and team, when mapped), resources by id or
+// by team, fn and empl. Unmatched rows are created at the end of their list.
+// Empty cells leave fields unchanged. Teams are resolved by name and blockers
+// by task name or sheet row number. With commit and no conflicts the changes
+// are written through UpdateData as one change set.
+func (s *Service) ImportTable(documentID uuid.UUID, req *models.TableImportRequest) (*models.TableImportResponse, error) {
+	records, err := parseTable(req.Content, req.Delimiter)
+	if err != nil {
+		return &models.TableImportResponse{Success: false, Error: err.Error()}, nil
+	}
+	if len(records) == 0 || (!req.NoHeader && len(records) == 1) {
+		return &models.TableImportResponse{Success: false, Error: "The table has no rows"}, nil
+	}
+
+	width := 0
+	for _, record := range records {
+		width = max(width, len(record))
+	}
+	var header []string
+	firstLine := 1
+	if !req.NoHeader {
+		header, records, firstLine = records[0], records[1:], 2
+	}
+	columns, err := tableColumns(header, width, req.Mapping)
+	if err != nil {
+		return &models.TableImportResponse{Success: false, Error: err.Error()}, nil
+	}
+
+	data, err := s.repo.GetAllData(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all data: %w", err)
+	}
+
+	imp := newTableImport(data)
+	for i, record := range records {
+		if row := imp.read(firstLine+i, record, columns); row != nil {
+			imp.rows = append(imp.rows, row)
+		}
+	}
+	imp.resolveBlockers()
+
+	response := &models.TableImportResponse{
+		Version: data.Version,
+		Success: true,
+		Columns: columns,
+		Rows:    make([]models.TableImportRow, 0, len(imp.rows)),
+	}
+	for _, row := range imp.rows {
+		if len(row.result.Conflicts) > 0 {
+			row.result.Action = models.TableImportConflict
+		}
+		switch row.result.Action {
+		case models.TableImportCreate:
+			response.Creates++
+		case models.TableImportUpdate:
+			response.Updates++
+		case models.TableImportUnchanged:
+			response.Unchanged++
+		case models.TableImportConflict:
+			response.Conflicts++
+		}
+	}
+
+	if req.Commit {
+		if err := s.commitTable(documentID, req, imp, response); err != nil {
+			return nil, err
+		}
+	}
+	for _, row := range imp.rows {
+		response.Rows = append(response.Rows, row.result)
+	}
+	return response, nil
+}
+
+// commitTable writes the imported rows unless some have conflicts
+func (s *Service) commitTable(documentID uuid.UUID, req *models.TableImportRequest, imp *tableImport, response *models.TableImportResponse) error {
+	if response.Conflicts > 0 {
+		response.Success = false
+		response.Error = fmt.Sprintf("%d rows have conflicts; nothing was imported", response.Conflicts)
+		return nil
+	}
+	if response.Creates+response.Updates == 0 {
+		response.Committed = true
+		return nil
+	}
+
+	update := &models.UpdateRequest{Version: req.Version, UserID: req.UserID, Meta: req.Meta}
+	var resourceLinks []models.ResourceUpdate
+	var taskLinks []models.TaskUpdate
+
+	// Created rows are appended to their list: each is linked to the previous
+	// one on insert and the previous one to it afterwards, as the foreign keys
+	// require the neighbour to exist
+	var lastResource, lastTask *uuid.UUID
+	if n := len(imp.data.Resources); n > 0 {
+		lastResource = &imp.data.Resources[n-1].ID
+	}
+	if n := len(imp.data.Tasks); n > 0 {
+		lastTask = &imp.data.Tasks[n-1].ID
+	}
+	for _, row := range imp.rows {
+		switch {
+		case row.result.Action == models.TableImportUpdate && row.resource != nil:
+			update.Resources = append(update.Resources, *row.resource)
+		case row.result.Action == models.TableImportUpdate && row.task != nil:
+			update.Tasks = append(update.Tasks, *row.task)
+		case row.result.Action == models.TableImportCreate && row.resource != nil:
+			row.resource.PrevID = lastResource
+			update.Resources = append(update.Resources, *row.resource)
+			if lastResource != nil {
+				resourceLinks = append(resourceLinks, models.ResourceUpdate{ID: *lastResource, NextID: &row.resource.ID})
+			}
+			lastResource = &row.resource.ID
+		case row.result.Action == models.TableImportCreate && row.task != nil:
+			row.task.PrevID = lastTask
+			update.Tasks = append(update.Tasks, *row.task)
+			if lastTask != nil {
+				taskLinks = append(taskLinks, models.TaskUpdate{ID: *lastTask, NextID: &row.task.ID})
+			}
+			lastTask = &row.task.ID
+		}
+	}
+	update.Resources = append(update.Resources, resourceLinks...)
+	update.Tasks = append(update.Tasks, taskLinks...)
+
+	result, err := s.UpdateData(documentID, update)
+	if err != nil {
+		return err
+	}
+	response.Success = result.Success
+	response.Error = result.Error
+	response.Forbidden = result.Forbidden
+	if !result.Success {
+		return nil
+	}
+	response.Committed = true
+	response.Version = result.Version
+	for _, row := range imp.rows {
+		if row.result.Action == models.TableImportCreate {
+			id := row.id()
+			row.result.ID = &id
+		}
+	}
+	return nil
+}
+
+// parseTable reads CSV or TSV text; the delimiter is detected from the first
+// line when not given
+func parseTable(content, delimiter string) ([][]string, error) {
+	content = strings.TrimPrefix(content, "\uFEFF")
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("content is required")
+	}
+
+	var comma rune
+	switch delimiter {
+	case "":
+		firstLine, _, _ := strings.Cut(content, "\n")
+		switch {
+		case strings.Contains(firstLine, "\t"):
+			comma = '\t'
+		case strings.Count(firstLine, ";") > strings.Count(firstLine, ","):
+			comma = ';'
+		default:
+			comma = ','
+		}
+	case ",", ";", "\t":
+		comma = rune(delimiter[0])
+	default:
+		return nil, fmt.Errorf("unsupported delimiter %q: use \",\", \";\" or \"\\t\"", delimiter)
+	}
+
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse table: %w", err)
+		}
+		records = append(records, record)
+	}
+}
+
+// tableColumns maps the columns to fields: by the mapping, keyed by header or
+// 1-based column number, otherwise by known headers
+func tableColumns(header []string, width int, mapping map[string]string) ([]models.TableImportColumn, error) {
+	columns := make([]models.TableImportColumn, width)
+	used := make(map[string]bool, len(mapping))
+	byField := make(map[string]int)
+	for i := range columns {
+		column := &columns[i]
+		column.Number = i + 1
+		if i < len(header) {
+			column.Header = strings.TrimSpace(header[i])
+		}
+
+		field, mapped := "", false
+		for key, value := range mapping {
+			key = strings.TrimSpace(key)
+			if key == strconv.Itoa(column.Number) || (column.Header != "" && strings.EqualFold(key, column.Header)) {
+				field, mapped = strings.TrimSpace(value), true
+				used[key] = true
+				break
+			}
+		}
+		if !mapped {
+			lower := strings.ToLower(column.Header)
+			field = importHeaders[lower]
+			if match := weekHeaderPattern.FindStringSubmatch(lower); match != nil {
+				field = "#" + match[1]
+			}
+		}
+		if field == "" {
+			continue
+		}
+		if !importFields[field] {
+			if match := weekFieldPattern.FindStringSubmatch(field); match == nil || match[1] == "0" {
+				return nil, fmt.Errorf("column %d is mapped to unknown field %q", column.Number, field)
+			}
+		}
+		if other, ok := byField[field]; ok {
+			return nil, fmt.Errorf("columns %d and %d are both mapped to %s", other, column.Number, field)
+		}
+		byField[field] = column.Number
+		column.Field = field
+	}
+
+	for key := range mapping {
+		if !used[strings.TrimSpace(key)] {
+			return nil, fmt.Errorf("mapped column %q not found in the table", key)
+		}
+	}
+	return columns, nil
+}
+
+// tableImport holds the plan an imported table is matched against
+type tableImport struct {
+	data      *models.DataResponse
+	calendar  *planning.Calendar
+	teams     map[string]uuid.UUID // lower-case name -> id
+	tasks     map[uuid.UUID]*models.Task
+	resources map[uuid.UUID]*models.Resource
+	position  map[uuid.UUID]int // row position of tasks, created ones after the existing
+	claimed   map[uuid.UUID]int // existing row -> line updating it
+	rows      []*importedRow
+}
+
+// importedRow is a row of the table with the change it makes
+type importedRow struct {
+	result   models.TableImportRow
+	cells    map[string]string // field -> cell
+	weeks    map[int]string    // 0-based week -> cell
+	task     *models.TaskUpdate
+	resource *models.ResourceUpdate
+	current  *models.Task // Updated task
+}
+
+func (row *importedRow) id() uuid.UUID {
+	if row.task != nil {
+		return row.task.ID
+	}
+	return row.resource.ID
+}
+
+func (row *importedRow) conflict(format string, args ...interface{}) {
+	row.result.Conflicts = append(row.result.Conflicts, fmt.Sprintf(format, args...))
+}
+
+func (row *importedRow) changed(field string) {
+	row.result.Fields = append(row.result.Fields, field)
+}
+
+func newTableImport(data *models.DataResponse) *tableImport {
+	imp := &tableImport{
+		data:      data,
+		calendar:  planning.NewCalendar(data.Sprints),
+		teams:     make(map[string]uuid.UUID, len(data.Teams)),
+		tasks:     make(map[uuid.UUID]*models.Task, len(data.Tasks)),
+		resources: make(map[uuid.UUID]*models.Resource, len(data.Resources)),
+		position:  make(map[uuid.UUID]int, len(data.Tasks)),
+		claimed:   make(map[uuid.UUID]int),
+	}
+	for _, team := range data.Teams {
+		if team.Name != nil {
+			imp.teams[strings.ToLower(strings.TrimSpace(*team.Name))] = team.ID
+		}
+	}
+	for i := range data.Tasks {
+		imp.tasks[data.Tasks[i].ID] = &data.Tasks[i]
+		imp.position[data.Tasks[i].ID] = i
+	}
+	for i := range data.Resources {
+		imp.resources[data.Resources[i].ID] = &data.Resources[i]
+	}
+	return imp
+}
+
+// read turns a record into a task or resource change; blank rows are skipped
+func (imp *tableImport) read(line int, record []string, columns []models.TableImportColumn) *importedRow {
+	row := &importedRow{
+		result: models.TableImportRow{Line: line},
+		cells:  make(map[string]string),
+		weeks:  make(map[int]string),
+	}
+	blank := true
+	for i, cell := range record {
+		cell = strings.TrimSpace(cell)
+		if cell != "" {
+			blank = false
+		}
+		field := columns[i].Field
+		if match := weekFieldPattern.FindStringSubmatch(field); match != nil {
+			week, _ := strconv.Atoi(match[1])
+			row.weeks[week-1] = cell
+		} else if field != "" {
+			row.cells[field] = cell
+		}
+	}
+	if blank {
+		return nil
+	}
+
+	switch strings.ToLower(row.cells[importFieldType]) {
+	case "", "task", "задача":
+		row.result.Kind = models.RowKindTask
+		imp.readTask(row)
+	case "resource", "ресурс":
+		row.result.Kind = models.RowKindResource
+		imp.readResource(row)
+	default:
+		row.result.Kind = models.RowKindTask
+		row.result.Action = models.TableImportConflict
+		row.conflict("unknown type %q, expected Задача or Ресурс", row.cells[importFieldType])
+	}
+	return row
+}
+
+// readTask matches a task row and collects the fields it changes
+func (imp *tableImport) readTask(row *importedRow) {
+	name := row.cells[importFieldTask]
+	row.result.Name = name
+
+	var teamID *uuid.UUID
+	if value := row.cells[importFieldTeam]; value != "" {
+		if id, ok := imp.teams[strings.ToLower(value)]; ok {
+			teamID = &id
+		} else {
+			row.conflict("unknown team %q", value)
+		}
+	}
+
+	current := imp.matchTask(row, name, teamID)
+	row.current = current
+	task := &models.TaskUpdate{}
+	row.task = task
+	if current != nil {
+		task.ID = current.ID
+		row.result.ID = &task.ID
+		row.result.Action = models.TableImportUpdate
+		if name == "" && current.TaskName != nil {
+			row.result.Name = *current.TaskName
+		}
+	} else {
+		task.ID = uuid.New()
+		row.result.Action = models.TableImportCreate
+		if name == "" {
+			row.conflict("task name is required to create a task")
+		}
+		imp.position[task.ID] = len(imp.position)
+	}
+	if current == nil {
+		current = &models.Task{}
+	}
+
+	setString(row, importFieldTask, current.TaskName, &task.TaskName)
+	setString(row, importFieldEpic, current.Epic, &task.Epic)
+	setString(row, importFieldFn, current.Function, &task.Function)
+	setString(row, importFieldEmpl, current.Employee, &task.Employee)
+	if teamID != nil && (current.TeamID == nil || *current.TeamID != *teamID) {
+		task.TeamID = teamID
+		row.changed(importFieldTeam)
+	}
+
+	if value := row.cells[importFieldStatus]; value != "" {
+		var status *models.TaskStatus
+		for _, known := range []models.TaskStatus{models.TaskStatusTodo, models.TaskStatusBacklog, models.TaskStatusCancelled} {
+			if strings.EqualFold(value, string(known)) {
+				status = &known
+			}
+		}
+		if status == nil {
+			row.conflict("unknown status %q", value)
+		} else if current.Status == nil || *current.Status != *status {
+			task.Status = status
+			row.changed(importFieldStatus)
+		}
+	}
+
+	setNumber(row, importFieldPlanEmpl, current.PlanEmpl, &task.PlanEmpl, false)
+	setNumber(row, importFieldPlanWeeks, current.PlanWeeks, &task.PlanWeeks, true)
+
+	if value := row.cells[importFieldAuto]; value != "" {
+		if enabled, ok := parseImportBool(value); !ok {
+			row.conflict("invalid %s %q", importFieldAuto, value)
+		} else if current.AutoPlanEnabled == nil || *current.AutoPlanEnabled != enabled {
+			task.AutoPlanEnabled = &enabled
+			row.changed(importFieldAuto)
+		}
+	}
+
+	if weeks, changed := imp.readWeeks(row, current.Weeks); changed {
+		task.Weeks = &weeks
+		row.changed("weeks")
+		// Derived fields as the client computes them (spec §6.3)
+		fact := 0.0
+		var startWeek, endWeek *int
+		for i, value := range weeks {
+			fact += value
+			if value > 0 {
+				week := i + 1
+				if startWeek == nil {
+					startWeek = &week
+				}
+				endWeek = &week
+			}
+		}
+		sprints := pq.StringArray(imp.calendar.SprintsBetween(startWeek, endWeek))
+		task.Fact, task.StartWeek, task.EndWeek, task.SprintsAuto = &fact, startWeek, endWeek, &sprints
+	}
+
+	if row.result.Action == models.TableImportCreate {
+		// Defaults of the columns, which an insert through UpdateData does not apply
+		if task.Status == nil {
+			status := models.TaskStatusTodo
+			task.Status = &status
+		}
+		if task.AutoPlanEnabled == nil {
+			// Imported weeks would be overwritten by auto-planning
+			enabled := task.Weeks == nil
+			task.AutoPlanEnabled = &enabled
+		}
+		zero, zeroWeeks := 0.0, 0.0
+		if task.PlanEmpl == nil {
+			task.PlanEmpl = &zero
+		}
+		if task.PlanWeeks == nil {
+			task.PlanWeeks = &zeroWeeks
+		}
+		if task.Weeks == nil {
+			task.Weeks, task.Fact, task.SprintsAuto = &pq.Float64Array{}, &zero, &pq.StringArray{}
+		}
+		task.BlockerIDs, task.WeekBlockers = &pq.StringArray{}, &pq.Int64Array{}
+	} else if len(row.result.Fields) == 0 && row.cells[importFieldBlockers] == "" {
+		row.result.Action = models.TableImportUnchanged
+	}
+}
+
+// matchTask finds the task a row updates: by id, or by name within the team
+func (imp *tableImport) matchTask(row *importedRow, name string, teamID *uuid.UUID) *models.Task {
+	var match *models.Task
+	if value := row.cells[importFieldID]; value != "" {
+		id, err := uuid.Parse(value)
+		if match = imp.tasks[id]; err != nil || match == nil {
+			row.conflict("unknown task id %q", value)
+			return nil
+		}
+	} else if name != "" {
+		var candidates []*models.Task
+		for i := range imp.data.Tasks {
+			task := &imp.data.Tasks[i]
+			if task.TaskName == nil || !strings.EqualFold(strings.TrimSpace(*task.TaskName), name) {
+				continue
+			}
+			if teamID != nil && (task.TeamID == nil || *task.TeamID != *teamID) {
+				continue
+			}
+			candidates = append(candidates, task)
+		}
+		if len(candidates) > 1 {
+			row.conflict("%d tasks are named %q; add an id or team column", len(candidates), name)
+			return nil
+		}
+		if len(candidates) == 1 {
+			match = candidates[0]
+		}
+	}
+	if match == nil {
+		return nil
+	}
+	if line, ok := imp.claimed[match.ID]; ok {
+		row.conflict("row %d already updates this task", line)
+		return nil
+	}
+	imp.claimed[match.ID] = row.result.Line
+	return match
+}
+
+// readResource matches a resource row by id or by team, fn and empl and
+// collects the fields it changes
+func (imp *tableImport) readResource(row *importedRow) {
+	var teamIDs pq.StringArray
+	var teamNames []string
+	for _, name := range strings.Split(row.cells[importFieldTeam], ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		teamNames = append(teamNames, name)
+		if id, ok := imp.teams[strings.ToLower(name)]; ok {
+			teamIDs = append(teamIDs, id.String())
+		} else {
+			row.conflict("unknown team %q", name)
+		}
+	}
+	fn, empl := row.cells[importFieldFn], row.cells[importFieldEmpl]
+	row.result.Name = strings.Trim(strings.Join(teamNames, ", ")+" / "+fn+" / "+empl, " /")
+
+	current := imp.matchResource(row, teamIDs, fn, empl)
+	resource := &models.ResourceUpdate{}
+	row.resource = resource
+	if current != nil {
+		resource.ID = current.ID
+		row.result.ID = &resource.ID
+		row.result.Action = models.TableImportUpdate
+	} else {
+		resource.ID = uuid.New()
+		row.result.Action = models.TableImportCreate
+		if len(teamNames) == 0 || fn == "" {
+			row.conflict("team and fn are required to create a resource")
+		}
+		current = &models.Resource{}
+	}
+
+	if len(teamIDs) > 0 && !equalStrings(sortedCopy(teamIDs), sortedCopy(current.TeamUUIDs)) {
+		resource.TeamIDs = &teamIDs
+		row.changed(importFieldTeam)
+	}
+	setString(row, importFieldFn, current.Function, &resource.Function)
+	setString(row, importFieldEmpl, current.Employee, &resource.Employee)
+
+	if weeks, changed := imp.readWeeks(row, current.Weeks); changed {
+		resource.Weeks = &weeks
+		row.changed("weeks")
+	}
+
+	if row.result.Action == models.TableImportCreate {
+		if resource.Weeks == nil {
+			resource.Weeks = &pq.Float64Array{}
+		}
+	} else if len(row.result.Fields) == 0 {
+		row.result.Action = models.TableImportUnchanged
+	}
+}
+
+// matchResource finds the resource a row updates: by id, or by its teams, fn and empl
+func (imp *tableImport) matchResource(row *importedRow, teamIDs pq.StringArray, fn, empl string) *models.Resource {
+	var match *models.Resource
+	if value := row.cells[importFieldID]; value != "" {
+		id, err := uuid.Parse(value)
+		if match = imp.resources[id]; err != nil || match == nil {
+			row.conflict("unknown resource id %q", value)
+			return nil
+		}
+	} else if len(teamIDs) > 0 && fn != "" {
+		teams := sortedCopy(teamIDs)
+		var candidates []*models.Resource
+		for i := range imp.data.Resources {
+			resource := &imp.data.Resources[i]
+			if !equalStrings(sortedCopy(resource.TeamUUIDs), teams) ||
+				!strings.EqualFold(stringValue(resource.Function), fn) ||
+				!strings.EqualFold(stringValue(resource.Employee), empl) {
+				continue
+			}
+			candidates = append(candidates, resource)
+		}
+		if len(candidates) > 1 {
+			row.conflict("%d resources match the team, fn and empl; add an id column", len(candidates))
+			return nil
+		}
+		if len(candidates) == 1 {
+			match = candidates[0]
+		}
+	}
+	if match == nil {
+		return nil
+	}
+	if line, ok := imp.claimed[match.ID]; ok {
+		row.conflict("row %d already updates this resource", line)
+		return nil
+	}
+	imp.claimed[match.ID] = row.result.Line
+	return match
+}
+
+// readWeeks applies the week cells of the row to the stored weeks; empty
+// cells are zero. The result covers the calendar and every stored week.
+func (imp *tableImport) readWeeks(row *importedRow, stored *pq.Float64Array) (pq.Float64Array, bool) {
+	if len(row.weeks) == 0 {
+		return nil, false
+	}
+	var current []float64
+	if stored != nil {
+		current = *stored
+	}
+	n := max(imp.calendar.Weeks, len(current))
+	for week := range row.weeks {
+		n = max(n, week+1)
+	}
+
+	weeks := padWeeks(current, n)
+	for week, cell := range row.weeks {
+		weeks[week] = 0
+		if cell == "" {
+			continue
+		}
+		value, ok := parseImportNumber(cell)
+		if !ok {
+			row.conflict("invalid value %q in week #%d", cell, week+1)
+			continue
+		}
+		weeks[week] = value
+	}
+	if equalFloats(padWeeks(current, n), weeks) {
+		return nil, false
+	}
+	return weeks, true
+}
+
+// resolveBlockers resolves blocker references of task rows once every row has
+// an id. References are sheet row numbers or task names, looked up among the
+// imported rows first. Blockers must be above the task (spec §5.2).
+func (imp *tableImport) resolveBlockers() {
+	byLine := make(map[int]*importedRow, len(imp.rows))
+	byName := make(map[string][]*importedRow)
+	for _, row := range imp.rows {
+		if row.task == nil {
+			continue
+		}
+		byLine[row.result.Line] = row
+		if row.cells[importFieldTask] != "" {
+			name := strings.ToLower(row.cells[importFieldTask])
+			byName[name] = append(byName[name], row)
+		}
+	}
+
+	for _, row := range imp.rows {
+		cell := row.cells[importFieldBlockers]
+		if row.task == nil || cell == "" {
+			continue
+		}
+		blockers := pq.StringArray{}
+		seen := make(map[uuid.UUID]bool)
+		for _, ref := range strings.FieldsFunc(cell, func(r rune) bool { return r == ',' || r == ';' || r == '\n' }) {
+			if ref = strings.TrimSpace(ref); ref == "" {
+				continue
+			}
+			id, ok := imp.resolveBlocker(row, ref, byLine, byName)
+			if !ok || seen[id] {
+				continue
+			}
+			seen[id] = true
+			switch {
+			case id == row.task.ID:
+				row.conflict("a task cannot block itself")
+			case imp.position[id] > imp.position[row.task.ID]:
+				row.conflict("blocker %q is below the task", ref)
+			default:
+				blockers = append(blockers, id.String())
+			}
+		}
+
+		var current []string
+		if row.current != nil && row.current.BlockerIDs != nil {
+			current = *row.current.BlockerIDs
+		}
+		if !equalStrings(sortedCopy(blockers), sortedCopy(current)) {
+			row.task.BlockerIDs = &blockers
+			row.changed(importFieldBlockers)
+			if row.result.Action == models.TableImportUnchanged {
+				row.result.Action = models.TableImportUpdate
+			}
+		} else if row.result.Action == models.TableImportUpdate && len(row.result.Fields) == 0 {
+			row.result.Action = models.TableImportUnchanged
+		}
+	}
+}
+
+// resolveBlocker resolves one blocker reference of a row
+func (imp *tableImport) resolveBlocker(row *importedRow, ref string, byLine map[int]*importedRow, byName map[string][]*importedRow) (uuid.UUID, bool) {
+	if match := rowRefPattern.FindStringSubmatch(ref); match != nil {
+		line, _ := strconv.Atoi(match[1])
+		blocker, ok := byLine[line]
+		if !ok {
+			row.conflict("blocker row %d is not an imported task", line)
+			return uuid.Nil, false
+		}
+		if len(blocker.result.Conflicts) > 0 {
+			row.conflict("blocker row %d has conflicts", line)
+			return uuid.Nil, false
+		}
+		return blocker.task.ID, true
+	}
+
+	if rows := byName[strings.ToLower(ref)]; len(rows) > 0 {
+		if len(rows) > 1 {
+			row.conflict("blocker %q matches rows %d and %d; refer to the row number", ref, rows[0].result.Line, rows[1].result.Line)
+			return uuid.Nil, false
+		}
+		if len(rows[0].result.Conflicts) > 0 {
+			row.conflict("blocker row %d has conflicts", rows[0].result.Line)
+			return uuid.Nil, false
+		}
+		return rows[0].task.ID, true
+	}
+
+	var matches []uuid.UUID
+	for _, task := range imp.data.Tasks {
+		if task.TaskName != nil && strings.EqualFold(strings.TrimSpace(*task.TaskName), ref) {
+			matches = append(matches, task.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		row.conflict("unknown blocker %q", ref)
+		return uuid.Nil, false
+	case 1:
+		return matches[0], true
+	default:
+		row.conflict("%d tasks are named %q; refer to the blocker by row number", len(matches), ref)
+		return uuid.Nil, false
+	}
+}
+
+// setString sets a text field from its cell when it differs from the current value
+func setString(row *importedRow, field string, current *string, target **string) {
+	value := row.cells[field]
+	if value == "" || (current != nil && *current == value) {
+		return
+	}
+	*target = &value
+	row.changed(field)
+}
+
+// setNumber sets a non-negative number field from its cell when it differs
+// from the current value
+func setNumber(row *importedRow, field string, current *float64, target **float64, integer bool) {
+	cell := row.cells[field]
+	if cell == "" {
+		return
+	}
+	value, ok := parseImportNumber(cell)
+	if !ok || (integer && value != math.Trunc(value)) {
+		row.conflict("invalid %s %q", field, cell)
+		return
+	}
+	if current != nil && *current == value {
+		return
+	}
+	*target = &value
+	row.changed(field)
+}
+
+// parseImportNumber parses a non-negative number as spreadsheets format it,
+// with a decimal comma or grouping spaces
+func parseImportNumber(cell string) (float64, bool) {
+	cell = strings.NewReplacer(" ", "", "\u00a0", "", ",", ".").Replace(cell)
+	value, err := strconv.ParseFloat(cell, 64)
+	if err != nil || value < 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, false
+	}
+	return value, true
+}
+
+func parseImportBool(cell string) (bool, bool) {
+	switch strings.ToLower(cell) {
+	case "true", "yes", "on", "1", "да", "✓":
+		return true, true
+	case "false", "no", "off", "0", "нет":
+		return false, true
+	}
+	return false, false
+}
+
+func sortedCopy(values []string) []string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
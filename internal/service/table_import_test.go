@@ -0,0 +1,243 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"roadmap/internal/models"
+)
+
+func TestParseTable(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		delimiter string
+		want      [][]string
+		wantErr   string
+	}{
+		{
+			name:    "tab detected",
+			content: "Task\tFn\nAPI\tBE, QA\n",
+			want:    [][]string{{"Task", "Fn"}, {"API", "BE, QA"}},
+		},
+		{
+			name:    "semicolon detected",
+			content: "Task;Plan empl\nAPI;1,5\n",
+			want:    [][]string{{"Task", "Plan empl"}, {"API", "1,5"}},
+		},
+		{
+			name:    "comma by default",
+			content: "Task,Fn\nAPI,BE",
+			want:    [][]string{{"Task", "Fn"}, {"API", "BE"}},
+		},
+		{
+			name:    "byte order mark of the export",
+			content: "\uFEFFTask,Fn\nAPI,BE\n",
+			want:    [][]string{{"Task", "Fn"}, {"API", "BE"}},
+		},
+		{
+			name:    "quoted cells with delimiters and line breaks",
+			content: "Task,Blockers\n\"API, v2\",\"Design\nBuild\"\n",
+			want:    [][]string{{"Task", "Blockers"}, {"API, v2", "Design\nBuild"}},
+		},
+		{
+			name:    "rows of different width",
+			content: "Task,Fn,Empl\nAPI\n",
+			want:    [][]string{{"Task", "Fn", "Empl"}, {"API"}},
+		},
+		{
+			name:      "given delimiter",
+			content:   "Task;Fn,Empl\nAPI;BE,Ann\n",
+			delimiter: ",",
+			want:      [][]string{{"Task;Fn", "Empl"}, {"API;BE", "Ann"}},
+		},
+		{
+			name:      "unsupported delimiter",
+			content:   "Task|Fn\n",
+			delimiter: "|",
+			wantErr:   "unsupported delimiter",
+		},
+		{
+			name:    "blank content",
+			content: " \n\t\n",
+			wantErr: "content is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTable(tt.content, tt.delimiter)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  []string
+		width   int
+		mapping map[string]string
+		want    []string // field per column
+		wantErr string
+	}{
+		{
+			name:   "headers of the CSV export",
+			header: []string{"Type", "Task", "Plan weeks", "Blocker names", "Fact", "#3 Q1S2", "#4"},
+			width:  7,
+			want:   []string{"type", "task", "planWeeks", "blockers", "", "#3", "#4"},
+		},
+		{
+			name:   "headers are trimmed and case-insensitive",
+			header: []string{" TASK ", "Тип", "AutoPlanEnabled"},
+			width:  3,
+			want:   []string{"task", "type", "autoPlanEnabled"},
+		},
+		{
+			name:    "mapping by column number and by header",
+			header:  []string{"Название", "Функция", "Неделя 1"},
+			width:   3,
+			mapping: map[string]string{"1": "task", "функция": "fn", "3": "#1"},
+			want:    []string{"task", "fn", "#1"},
+		},
+		{
+			name:    "mapping overrides a known header",
+			header:  []string{"Task", "Epic"},
+			width:   2,
+			mapping: map[string]string{"Task": "epic", "Epic": "task"},
+			want:    []string{"epic", "task"},
+		},
+		{
+			name:    "columns without header",
+			width:   2,
+			mapping: map[string]string{"2": "task"},
+			want:    []string{"", "task"},
+		},
+		{
+			name:   "columns wider than the header",
+			header: []string{"Task"},
+			width:  2,
+			want:   []string{"task", ""},
+		},
+		{
+			name:    "unknown field",
+			header:  []string{"Task"},
+			width:   1,
+			mapping: map[string]string{"Task": "owner"},
+			wantErr: `column 1 is mapped to unknown field "owner"`,
+		},
+		{
+			name:    "week zero",
+			header:  []string{"Week"},
+			width:   1,
+			mapping: map[string]string{"1": "#0"},
+			wantErr: `column 1 is mapped to unknown field "#0"`,
+		},
+		{
+			name:    "field mapped twice",
+			header:  []string{"Task", "Name"},
+			width:   2,
+			mapping: map[string]string{"Name": "task"},
+			wantErr: "columns 1 and 2 are both mapped to task",
+		},
+		{
+			name:    "mapped column missing",
+			header:  []string{"Task"},
+			width:   1,
+			mapping: map[string]string{"Owner": "empl"},
+			wantErr: `mapped column "Owner" not found in the table`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := tableColumns(tt.header, tt.width, tt.mapping)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			fields := make([]string, len(columns))
+			for i, column := range columns {
+				if column.Number != i+1 {
+					t.Errorf("column %d has number %d", i+1, column.Number)
+				}
+				fields[i] = column.Field
+			}
+			if !reflect.DeepEqual(fields, tt.want) {
+				t.Errorf("got fields %q, want %q", fields, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveBlocker(t *testing.T) {
+	name := func(value string) *string { return &value }
+	design := models.Task{ID: uuid.New(), TaskName: name("Design")}
+	imp := newTableImport(&models.DataResponse{Tasks: []models.Task{
+		design,
+		{ID: uuid.New(), TaskName: name("Build")},
+		{ID: uuid.New(), TaskName: name(" build ")},
+	}})
+
+	importedTask := func(line int, conflicts ...string) *importedRow {
+		return &importedRow{
+			result: models.TableImportRow{Line: line, Conflicts: conflicts},
+			task:   &models.TaskUpdate{ID: uuid.New()},
+		}
+	}
+	api, broken, first, second := importedTask(2), importedTask(3, "unknown team"), importedTask(4), importedTask(5)
+	byLine := map[int]*importedRow{2: api, 3: broken, 4: first, 5: second}
+	byName := map[string][]*importedRow{"api": {api}, "broken": {broken}, "twice": {first, second}}
+
+	tests := []struct {
+		ref          string
+		want         uuid.UUID
+		wantConflict string
+	}{
+		{ref: "2", want: api.task.ID},
+		{ref: "#2", want: api.task.ID},
+		{ref: "API", want: api.task.ID},
+		{ref: "design", want: design.ID},
+		{ref: "7", wantConflict: "blocker row 7 is not an imported task"},
+		{ref: "3", wantConflict: "blocker row 3 has conflicts"},
+		{ref: "Broken", wantConflict: "blocker row 3 has conflicts"},
+		{ref: "Twice", wantConflict: `blocker "Twice" matches rows 4 and 5; refer to the row number`},
+		{ref: "Build", wantConflict: `2 tasks are named "Build"; refer to the blocker by row number`},
+		{ref: "Deploy", wantConflict: `unknown blocker "Deploy"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			row := importedTask(6)
+			got, ok := imp.resolveBlocker(row, tt.ref, byLine, byName)
+			if tt.wantConflict != "" {
+				if ok || !reflect.DeepEqual(row.result.Conflicts, []string{tt.wantConflict}) {
+					t.Fatalf("got %s, %v with conflicts %q, want conflict %q", got, ok, row.result.Conflicts, tt.wantConflict)
+				}
+				return
+			}
+			if !ok || got != tt.want || len(row.result.Conflicts) > 0 {
+				t.Errorf("got %s, %v with conflicts %q, want %s", got, ok, row.result.Conflicts, tt.want)
+			}
+		})
+	}
+}
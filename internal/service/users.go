@@ -0,0 +1,103 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+
+	"roadmap/internal/models"
+)
+
+// ErrInvalidUser is returned for user display info that fails validation
+var ErrInvalidUser = errors.New("invalid user")
+
+var avatarColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// avatarPalette holds the default avatar colors
+var avatarPalette = []string{
+	"#E57373", "#F06292", "#BA68C8", "#7986CB", "#4FC3F7",
+	"#4DB6AC", "#81C784", "#DCE775", "#FFB74D", "#A1887F",
+}
+
+// GetUsers returns all registered users
+func (s *Service) GetUsers() ([]models.User, error) {
+	users, err := s.repo.GetUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	return users, nil
+}
+
+// GetUser returns a registered user or ErrNotFound
+func (s *Service) GetUser(id string) (*models.User, error) {
+	user, err := s.repo.GetUser(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("user %s: %w", id, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user %s: %w", id, err)
+	}
+	return user, nil
+}
+
+// RegisterUser creates or updates the display info of a user
+func (s *Service) RegisterUser(req *models.RegisterUserRequest) (*models.User, error) {
+	user := &models.User{
+		ID:          req.UserID,
+		DisplayName: strings.TrimSpace(req.DisplayName),
+		AvatarColor: strings.TrimSpace(req.AvatarColor),
+	}
+	if user.DisplayName == "" || len(user.DisplayName) > 255 {
+		return nil, fmt.Errorf("displayName must be 1 to 255 characters: %w", ErrInvalidUser)
+	}
+	if req.Email != nil {
+		if email := strings.TrimSpace(*req.Email); email != "" {
+			if !strings.Contains(email, "@") || len(email) > 255 {
+				return nil, fmt.Errorf("email %q is not valid: %w", email, ErrInvalidUser)
+			}
+			user.Email = &email
+		}
+	}
+	if user.AvatarColor == "" {
+		user.AvatarColor = defaultAvatarColor(user.ID)
+	} else if !avatarColorPattern.MatchString(user.AvatarColor) {
+		return nil, fmt.Errorf("avatarColor must be #RRGGBB: %w", ErrInvalidUser)
+	}
+
+	registered, err := s.repo.UpsertUser(user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register user %s: %w", user.ID, err)
+	}
+	return registered, nil
+}
+
+// userDirectory returns display info of the registered users among the ids
+func (s *Service) userDirectory(ids []*string) (models.UserDirectory, error) {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, id := range ids {
+		if id != nil && !seen[*id] {
+			seen[*id] = true
+			unique = append(unique, *id)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, nil
+	}
+
+	directory, err := s.repo.GetUserDirectory(unique)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+	return directory, nil
+}
+
+// defaultAvatarColor picks a stable palette color for the user id
+func defaultAvatarColor(id string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(id))
+	return avatarPalette[hash.Sum32()%uint32(len(avatarPalette))]
+}
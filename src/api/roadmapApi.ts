@@ -1,6 +1,8 @@
 // API функции для работы с roadmap данными
 import type { 
   ApiResponse, 
+  DiffResponse,
+  RoadmapChanges,
   RoadmapData, 
   SaveResponse 
 } from './types';
@@ -130,3 +132,42 @@ export async function fetchVersion(): Promise<ApiResponse<{ version: number }>>
     };
   }
 }
+
+// Загружает изменения начиная с fromVersion, проходя все страницы по nextCursor.
+// Если сервер отвечает fetchFullData (409) или resyncRequired (410), вместо
+// изменений загружаются полные данные.
+export async function fetchRoadmapChanges(fromVersion: number): Promise<ApiResponse<RoadmapChanges>> {
+  try {
+    const changes: RoadmapChanges['changes'] = [];
+    let cursor: number | undefined;
+    for (;;) {
+      const query = cursor !== undefined ? `?cursor=${cursor}` : '';
+      const response = await fetch(`${API_BASE_URL}/api/v1/data/diff/${fromVersion}${query}`);
+      const page: DiffResponse = await response.json();
+
+      if (page.fetchFullData || page.resyncRequired) {
+        const full = await fetchRoadmapData();
+        if (full.error) {
+          throw new Error(full.error);
+        }
+        return { data: { version: full.data.version, changes: [], fullData: full.data } };
+      }
+      if (!response.ok) {
+        throw new Error(`HTTP error! status: ${response.status}`);
+      }
+
+      changes.push(...page.changes);
+      if (!page.hasMore || page.nextCursor === undefined) {
+        // version is current only after the last page is applied
+        return { data: { version: page.version, changes } };
+      }
+      cursor = page.nextCursor;
+    }
+  } catch (error) {
+    console.error('Error fetching roadmap changes:', error);
+    return { 
+      data: { version: fromVersion, changes: [] }, 
+      error: error instanceof Error ? error.message : 'Unknown error' 
+    };
+  }
+}
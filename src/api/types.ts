@@ -85,3 +85,31 @@ export interface UseAutoSaveOptions {
   onSaveError?: (error: string) => void;
 }
 
+
+export interface ChangeLogEntry {
+  version: number;
+  table: string;
+  recordId: string;
+  operation: "INSERT" | "UPDATE" | "DELETE";
+  userId?: string;
+  changeSetId?: string;
+  oldData?: Record<string, unknown>;
+  newData?: Record<string, unknown>;
+  createdAt: string;
+}
+
+export interface DiffResponse {
+  version: number;
+  changes: ChangeLogEntry[];
+  hasMore: boolean;
+  nextCursor?: number; // Pass as ?cursor= to get the next page
+  resyncRequired?: boolean; // Requested version was compacted, fetch full data
+  fetchFullData?: boolean; // Diff would be larger than full data, fetch it instead
+}
+
+// Changes since a version, or the full data when the server asks to reload it
+export interface RoadmapChanges {
+  version: number;
+  changes: ChangeLogEntry[];
+  fullData?: RoadmapData;
+}